@@ -33,24 +33,53 @@ type OCIClusterAutoscalerSpec struct {
 
 	// ClusterAutoscaler configuration
 	ClusterAutoscaler ClusterAutoscalerConfig `json:"clusterAutoscaler,omitempty"`
+
+	// CSRApproval configures the approval of kubelet CSRs for the machines of the cluster
+	// +optional
+	CSRApproval CSRApprovalConfig `json:"csrApproval,omitempty"`
+
+	// Cleanup configures the garbage collection of the CSRs and Node objects left behind by deleted machines
+	// +optional
+	Cleanup CleanupConfig `json:"cleanup,omitempty"`
+
+	// NetworkPolicy configures the NetworkPolicies of the CAPI and CAPOCI namespaces
+	// +optional
+	NetworkPolicy NetworkPolicyConfig `json:"networkPolicy,omitempty"`
 }
 
 // OCIConfig contains OCI-specific configuration
+// +kubebuilder:validation:XValidation:rule="has(self.credentialsConfigRef) || (has(self.tenancyId) && has(self.region))",message="tenancyId and region are required unless credentialsConfigRef is set"
 type OCIConfig struct {
-	// TenancyID is the OCI tenancy OCID
-	TenancyID string `json:"tenancyId"`
+	// TenancyID is the OCI tenancy OCID. Defaults to the tenancy of the CredentialsConfigRef profile.
+	// +optional
+	TenancyID string `json:"tenancyId,omitempty"`
 
-	// UserID is the OCI user OCID
-	UserID string `json:"userId"`
+	// UserID is the OCI user OCID. Deprecated: use Auth.UserPrincipal.
+	// +optional
+	UserID string `json:"userId,omitempty"`
 
-	// Region is the OCI region
-	Region string `json:"region"`
+	// Region is the OCI region. Defaults to the region of the CredentialsConfigRef profile.
+	// +optional
+	Region string `json:"region,omitempty"`
 
-	// Fingerprint for the API key
-	Fingerprint string `json:"fingerprint"`
+	// Fingerprint for the API key. Deprecated: use Auth.UserPrincipal.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
 
-	// PrivateKeySecretRef references a secret containing the private key
-	PrivateKeySecretRef SecretRef `json:"privateKeySecretRef"`
+	// PrivateKeySecretRef references a secret containing the private key. Deprecated: use Auth.UserPrincipal.
+	// +optional
+	PrivateKeySecretRef SecretRef `json:"privateKeySecretRef,omitempty"`
+
+	// Auth selects how CAPOCI authenticates to OCI for the cluster. When unset, the user API key
+	// configured by UserID, Fingerprint and PrivateKeySecretRef is used.
+	// +optional
+	Auth *OCIAuthConfig `json:"auth,omitempty"`
+
+	// CredentialsConfigRef references a Secret holding an OCI CLI config file and the key files of its
+	// profiles. The tenancy, region and, when Auth is not set, the user API key of the selected profile are
+	// used for the fields of the OCI configuration that are not set.
+	// +optional
+	CredentialsConfigRef *OCIConfigFileRef `json:"credentialsConfigRef,omitempty"`
 
 	// CompartmentID is the OCI compartment OCID
 	CompartmentID string `json:"compartmentId"`
@@ -62,6 +91,22 @@ type OCIConfig struct {
 	Network NetworkConfig `json:"network"`
 }
 
+// OCIConfigFileRef references an OCI CLI config file stored in a Secret. The key_file of the profile is
+// looked up in the same Secret by its file name, e.g. key_file=~/.oci/oci_api_key.pem is read from the
+// oci_api_key.pem key.
+type OCIConfigFileRef struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+
+	// Key is the key of the config file in the secret. Defaults to config.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Profile is the profile of the config file to use. Defaults to DEFAULT.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+}
+
 // NetworkConfig contains OCI network configuration
 type NetworkConfig struct {
 	// VCNID is the Virtual Cloud Network OCID
@@ -94,6 +139,113 @@ type AutoscalingConfig struct {
 
 	// ShapeConfig contains flexible shape configuration
 	ShapeConfig *ShapeConfig `json:"shapeConfig,omitempty"`
+
+	// Priority is the priority expander priority of the default node group.
+	// Node groups with a higher priority are preferred when scaling up.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// Schedules change the size limits of the node group during recurring time windows
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Schedules []CapacitySchedule `json:"schedules,omitempty"`
+
+	// ScheduleOverride manually sets the size limits of the node group, taking precedence over Schedules
+	// +optional
+	ScheduleOverride *CapacityOverride `json:"scheduleOverride,omitempty"`
+
+	// NodeGroups are additional autoscaling node groups managed alongside the default one
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	NodeGroups []NodeGroupConfig `json:"nodeGroups,omitempty"`
+}
+
+// NodeGroupConfig contains the configuration of an additional autoscaling node group
+type NodeGroupConfig struct {
+	// Name of the node group, appended to the OCIClusterAutoscaler name to form the MachineDeployment name
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// MinNodes is the minimum number of nodes in the node group
+	// +kubebuilder:validation:Minimum=0
+	MinNodes int32 `json:"minNodes,omitempty"`
+
+	// MaxNodes is the maximum number of nodes in the node group
+	MaxNodes int32 `json:"maxNodes"`
+
+	// Shape is the OCI compute shape for the node group
+	Shape string `json:"shape"`
+
+	// ShapeConfig contains flexible shape configuration
+	ShapeConfig *ShapeConfig `json:"shapeConfig,omitempty"`
+
+	// Preemptible launches the node group instances as OCI preemptible instances
+	// +optional
+	Preemptible bool `json:"preemptible,omitempty"`
+
+	// Priority is the priority expander priority of the node group.
+	// Node groups with a higher priority are preferred when scaling up.
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// Schedules change the size limits of the node group during recurring time windows
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Schedules []CapacitySchedule `json:"schedules,omitempty"`
+
+	// ScheduleOverride manually sets the size limits of the node group, taking precedence over Schedules
+	// +optional
+	ScheduleOverride *CapacityOverride `json:"scheduleOverride,omitempty"`
+}
+
+// CapacitySchedule changes the size limits of a node group during a recurring time window.
+// When windows of several schedules overlap, the window that started last is applied and
+// ties are resolved in favour of the schedule listed first.
+type CapacitySchedule struct {
+	// Name of the schedule
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Schedule is a five field cron expression (minute hour day-of-month month day-of-week)
+	// that starts the time window
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the IANA time zone the schedule is evaluated in. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Duration is the length of the time window, at most 7 days
+	Duration metav1.Duration `json:"duration"`
+
+	// MinNodes is the minimum number of nodes during the time window. Defaults to the minimum of the node group.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinNodes *int32 `json:"minNodes,omitempty"`
+
+	// MaxNodes is the maximum number of nodes during the time window. Defaults to the maximum of the node group.
+	// +optional
+	MaxNodes *int32 `json:"maxNodes,omitempty"`
+}
+
+// CapacityOverride manually sets the size limits of a node group
+type CapacityOverride struct {
+	// MinNodes is the minimum number of nodes while the override is active. Defaults to the minimum of the node group.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinNodes *int32 `json:"minNodes,omitempty"`
+
+	// MaxNodes is the maximum number of nodes while the override is active. Defaults to the maximum of the node group.
+	// +optional
+	MaxNodes *int32 `json:"maxNodes,omitempty"`
+
+	// ExpiresAt is the time at which the override stops applying. Without it the override applies until removed.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 }
 
 // ShapeConfig contains OCI flexible shape configuration
@@ -107,10 +259,11 @@ type ShapeConfig struct {
 
 // CAPIConfig contains Cluster API configuration
 type CAPIConfig struct {
-	// Namespace where CAPI resources will be created
+	// Namespace where CAPI resources will be created. Defaults to capi-system.
 	Namespace string `json:"namespace,omitempty"`
 
-	// ClusterName is the name of the CAPI cluster
+	// ClusterName is the name of the CAPI cluster. Defaults to the name of the OCIClusterAutoscaler.
+	// Only kubelet CSRs of machines labelled with this cluster name in Namespace are approved.
 	ClusterName string `json:"clusterName,omitempty"`
 }
 
@@ -123,6 +276,126 @@ type ClusterAutoscalerConfig struct {
 	Resources *ResourceRequirements `json:"resources,omitempty"`
 }
 
+// OCIAuthType is the kind of OCI principal CAPOCI authenticates as
+// +kubebuilder:validation:Enum=UserPrincipal;InstancePrincipal;WorkloadIdentity
+type OCIAuthType string
+
+const (
+	// OCIAuthUserPrincipal authenticates with the API key of an OCI user
+	OCIAuthUserPrincipal OCIAuthType = "UserPrincipal"
+	// OCIAuthInstancePrincipal authenticates as the OCI instance CAPOCI runs on
+	OCIAuthInstancePrincipal OCIAuthType = "InstancePrincipal"
+	// OCIAuthWorkloadIdentity authenticates as the Kubernetes workload CAPOCI runs as
+	OCIAuthWorkloadIdentity OCIAuthType = "WorkloadIdentity"
+)
+
+// OCIAuthConfig selects the OCI principal used for the cluster. The operator creates an
+// OCIClusterIdentity for it and references it from the OCICluster.
+// +kubebuilder:validation:XValidation:rule="self.type == 'UserPrincipal' ? has(self.userPrincipal) : !has(self.userPrincipal)",message="userPrincipal must be set if and only if type is UserPrincipal"
+type OCIAuthConfig struct {
+	// Type is the kind of principal to authenticate as
+	Type OCIAuthType `json:"type"`
+
+	// UserPrincipal configures the user API key. Required when Type is UserPrincipal.
+	// +optional
+	UserPrincipal *UserPrincipalAuth `json:"userPrincipal,omitempty"`
+}
+
+// UserPrincipalAuth configures the API key of an OCI user
+type UserPrincipalAuth struct {
+	// UserID is the OCI user OCID
+	UserID string `json:"userId"`
+
+	// Fingerprint of the API key
+	Fingerprint string `json:"fingerprint"`
+
+	// PrivateKeySecretRef references a secret containing the private key
+	PrivateKeySecretRef SecretRef `json:"privateKeySecretRef"`
+
+	// PassphraseSecretRef references a secret containing the passphrase of an encrypted private key.
+	// The key in the secret defaults to passphrase.
+	// +optional
+	PassphraseSecretRef *SecretRef `json:"passphraseSecretRef,omitempty"`
+}
+
+// CSRApproverMode selects the controller approving the kubelet CSRs of the cluster machines
+// +kubebuilder:validation:Enum=Operator;Disabled;DelegateToMachineApprover
+type CSRApproverMode string
+
+const (
+	// CSRApproverModeOperator lets the operator approve the kubelet CSRs of the cluster machines
+	CSRApproverModeOperator CSRApproverMode = "Operator"
+
+	// CSRApproverModeDisabled leaves the kubelet CSRs of the cluster machines to be approved manually
+	CSRApproverModeDisabled CSRApproverMode = "Disabled"
+
+	// CSRApproverModeDelegateToMachineApprover leaves the kubelet CSRs of the cluster machines to an
+	// OpenShift cluster-machine-approver running with --apigroup=cluster.x-k8s.io and the namespace of the
+	// machines as --machine-namespace. The default cluster-machine-approver only approves the CSRs of
+	// Machine API machines.
+	CSRApproverModeDelegateToMachineApprover CSRApproverMode = "DelegateToMachineApprover"
+)
+
+// CSRApprovalConfig contains the limits applied when approving kubelet CSRs
+type CSRApprovalConfig struct {
+	// Mode selects the controller approving the kubelet CSRs of the cluster machines. Defaults to Operator.
+	// +optional
+	Mode CSRApproverMode `json:"mode,omitempty"`
+
+	// MaxPendingCSRs is the number of pending kubelet CSRs tolerated on top of one per OCIMachine
+	// that has no node yet. Approval pauses while more CSRs are pending. Defaults to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxPendingCSRs *int32 `json:"maxPendingCSRs,omitempty"`
+
+	// MaxApprovalsPerMachine is the number of CSRs approved for a single OCIMachine within
+	// ApprovalWindow. Further CSRs for the machine are not approved until older approvals
+	// leave the window. Defaults to 6.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxApprovalsPerMachine *int32 `json:"maxApprovalsPerMachine,omitempty"`
+
+	// ApprovalWindow is the period MaxApprovalsPerMachine applies to. Defaults to 1h.
+	// +optional
+	ApprovalWindow *metav1.Duration `json:"approvalWindow,omitempty"`
+
+	// DenyInvalid denies kubelet CSRs that match an OCIMachine of the cluster but fail validation,
+	// instead of leaving them pending. The denial reason names the failed check.
+	// +optional
+	DenyInvalid bool `json:"denyInvalid,omitempty"`
+}
+
+// NetworkPolicyConfig configures the NetworkPolicies of the CAPI and CAPOCI namespaces. Their traffic is
+// denied by default, except for webhook calls from the API server, metrics scraping by the platform
+// monitoring, DNS, and egress to the API server and the OCI endpoints.
+type NetworkPolicyConfig struct {
+	// Disabled leaves the CAPI and CAPOCI namespaces without NetworkPolicies
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// OCIEndpointCIDRs restricts the HTTPS egress of the CAPI controllers to the OCI API endpoints.
+	// Defaults to all addresses.
+	// +optional
+	OCIEndpointCIDRs []string `json:"ociEndpointCIDRs,omitempty"`
+}
+
+// CleanupConfig configures the garbage collection of the CSRs and Node objects of deleted machines
+type CleanupConfig struct {
+	// Disabled turns off the garbage collection
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// CSRRetention is how long CSRs approved or denied by the operator are kept after their
+	// OCIMachine is deleted. Defaults to 24h.
+	// +optional
+	CSRRetention *metav1.Duration `json:"csrRetention,omitempty"`
+
+	// NodeGracePeriod is how long a Node whose OCIMachine and Machine are deleted must have stopped
+	// reporting, or been marked as shut down, before it is deleted. Defaults to 15m.
+	// +optional
+	NodeGracePeriod *metav1.Duration `json:"nodeGracePeriod,omitempty"`
+}
+
 // ResourceRequirements contains resource requirements
 type ResourceRequirements struct {
 	// Requests describes the minimum amount of compute resources required
@@ -144,6 +417,9 @@ type SecretRef struct {
 // OCIClusterAutoscalerStatus defines the observed state of OCIClusterAutoscaler
 type OCIClusterAutoscalerStatus struct {
 	// Conditions represent the latest available observations of the autoscaler's current state
+	// +optional
+	// +listType=map
+	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
 	// Phase represents the current phase of the autoscaler
@@ -155,10 +431,70 @@ type OCIClusterAutoscalerStatus struct {
 	// ClusterAutoscalerDeployed indicates whether cluster-autoscaler is deployed
 	ClusterAutoscalerDeployed bool `json:"clusterAutoscalerDeployed,omitempty"`
 
+	// MonitoringConfigured indicates whether metrics Services, ServiceMonitors and alerting rules are deployed
+	MonitoringConfigured bool `json:"monitoringConfigured,omitempty"`
+
+	// NetworkPoliciesApplied indicates whether the NetworkPolicies of the CAPI and CAPOCI namespaces are deployed
+	NetworkPoliciesApplied bool `json:"networkPoliciesApplied,omitempty"`
+
+	// NodeGroups reports the size limits currently applied to each node group
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	NodeGroups []NodeGroupStatus `json:"nodeGroups,omitempty"`
+
+	// CredentialsRotationTime is when a change of the OCI credentials was last propagated to CAPOCI
+	// +optional
+	CredentialsRotationTime *metav1.Time `json:"credentialsRotationTime,omitempty"`
+
+	// Cleanup reports the CSRs and Node objects of deleted machines last garbage collected
+	// +optional
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+
 	// ObservedGeneration is the last generation observed by the controller
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// CleanupStatus reports the CSRs and Node objects of deleted machines last garbage collected
+type CleanupStatus struct {
+	// LastCleanupTime is when objects were last garbage collected
+	LastCleanupTime metav1.Time `json:"lastCleanupTime"`
+
+	// DeletedCSRs are the names of the CSRs deleted at LastCleanupTime
+	// +optional
+	DeletedCSRs []string `json:"deletedCSRs,omitempty"`
+
+	// DeletedNodes are the names of the Nodes deleted at LastCleanupTime
+	// +optional
+	DeletedNodes []string `json:"deletedNodes,omitempty"`
+
+	// OrphanedNodes are the names of the Nodes of deleted machines kept at LastCleanupTime because their
+	// instance is not known to be terminated
+	// +optional
+	OrphanedNodes []string `json:"orphanedNodes,omitempty"`
+}
+
+// NodeGroupStatus reports the size limits currently applied to a node group
+type NodeGroupStatus struct {
+	// Name is the name of the MachineDeployment backing the node group
+	Name string `json:"name"`
+
+	// MinNodes is the minimum number of nodes currently applied
+	MinNodes int32 `json:"minNodes"`
+
+	// MaxNodes is the maximum number of nodes currently applied
+	MaxNodes int32 `json:"maxNodes"`
+
+	// ActiveSchedule is the name of the schedule whose time window is applied, or Override when the
+	// schedule override is applied. It is empty when the node group limits are applied.
+	// +optional
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
+
+	// ActiveUntil is the end of the active time window or override
+	// +optional
+	ActiveUntil *metav1.Time `json:"activeUntil,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
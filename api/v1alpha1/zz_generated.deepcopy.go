@@ -33,6 +33,30 @@ func (in *AutoscalingConfig) DeepCopyInto(out *AutoscalingConfig) {
 		*out = new(ShapeConfig)
 		**out = **in
 	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]CapacitySchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScheduleOverride != nil {
+		in, out := &in.ScheduleOverride, &out.ScheduleOverride
+		*out = new(CapacityOverride)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroupConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingConfig.
@@ -60,6 +84,147 @@ func (in *CAPIConfig) DeepCopy() *CAPIConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRApprovalConfig) DeepCopyInto(out *CSRApprovalConfig) {
+	*out = *in
+	if in.MaxPendingCSRs != nil {
+		in, out := &in.MaxPendingCSRs, &out.MaxPendingCSRs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxApprovalsPerMachine != nil {
+		in, out := &in.MaxApprovalsPerMachine, &out.MaxApprovalsPerMachine
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ApprovalWindow != nil {
+		in, out := &in.ApprovalWindow, &out.ApprovalWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRApprovalConfig.
+func (in *CSRApprovalConfig) DeepCopy() *CSRApprovalConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRApprovalConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityOverride) DeepCopyInto(out *CapacityOverride) {
+	*out = *in
+	if in.MinNodes != nil {
+		in, out := &in.MinNodes, &out.MinNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityOverride.
+func (in *CapacityOverride) DeepCopy() *CapacityOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacitySchedule) DeepCopyInto(out *CapacitySchedule) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.MinNodes != nil {
+		in, out := &in.MinNodes, &out.MinNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacitySchedule.
+func (in *CapacitySchedule) DeepCopy() *CapacitySchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacitySchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupConfig) DeepCopyInto(out *CleanupConfig) {
+	*out = *in
+	if in.CSRRetention != nil {
+		in, out := &in.CSRRetention, &out.CSRRetention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.NodeGracePeriod != nil {
+		in, out := &in.NodeGracePeriod, &out.NodeGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupConfig.
+func (in *CleanupConfig) DeepCopy() *CleanupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CleanupStatus) DeepCopyInto(out *CleanupStatus) {
+	*out = *in
+	in.LastCleanupTime.DeepCopyInto(&out.LastCleanupTime)
+	if in.DeletedCSRs != nil {
+		in, out := &in.DeletedCSRs, &out.DeletedCSRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletedNodes != nil {
+		in, out := &in.DeletedNodes, &out.DeletedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrphanedNodes != nil {
+		in, out := &in.OrphanedNodes, &out.OrphanedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupStatus.
+func (in *CleanupStatus) DeepCopy() *CleanupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CleanupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 	*out = *in
@@ -95,6 +260,102 @@ func (in *NetworkConfig) DeepCopy() *NetworkConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
+	*out = *in
+	if in.OCIEndpointCIDRs != nil {
+		in, out := &in.OCIEndpointCIDRs, &out.OCIEndpointCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupConfig) DeepCopyInto(out *NodeGroupConfig) {
+	*out = *in
+	if in.ShapeConfig != nil {
+		in, out := &in.ShapeConfig, &out.ShapeConfig
+		*out = new(ShapeConfig)
+		**out = **in
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]CapacitySchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScheduleOverride != nil {
+		in, out := &in.ScheduleOverride, &out.ScheduleOverride
+		*out = new(CapacityOverride)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupConfig.
+func (in *NodeGroupConfig) DeepCopy() *NodeGroupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupStatus) DeepCopyInto(out *NodeGroupStatus) {
+	*out = *in
+	if in.ActiveUntil != nil {
+		in, out := &in.ActiveUntil, &out.ActiveUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupStatus.
+func (in *NodeGroupStatus) DeepCopy() *NodeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIAuthConfig) DeepCopyInto(out *OCIAuthConfig) {
+	*out = *in
+	if in.UserPrincipal != nil {
+		in, out := &in.UserPrincipal, &out.UserPrincipal
+		*out = new(UserPrincipalAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIAuthConfig.
+func (in *OCIAuthConfig) DeepCopy() *OCIAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OCIClusterAutoscaler) DeepCopyInto(out *OCIClusterAutoscaler) {
 	*out = *in
@@ -157,10 +418,13 @@ func (in *OCIClusterAutoscalerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OCIClusterAutoscalerSpec) DeepCopyInto(out *OCIClusterAutoscalerSpec) {
 	*out = *in
-	out.OCI = in.OCI
+	in.OCI.DeepCopyInto(&out.OCI)
 	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
 	out.CAPI = in.CAPI
 	in.ClusterAutoscaler.DeepCopyInto(&out.ClusterAutoscaler)
+	in.CSRApproval.DeepCopyInto(&out.CSRApproval)
+	in.Cleanup.DeepCopyInto(&out.Cleanup)
+	in.NetworkPolicy.DeepCopyInto(&out.NetworkPolicy)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIClusterAutoscalerSpec.
@@ -183,6 +447,22 @@ func (in *OCIClusterAutoscalerStatus) DeepCopyInto(out *OCIClusterAutoscalerStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CredentialsRotationTime != nil {
+		in, out := &in.CredentialsRotationTime, &out.CredentialsRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Cleanup != nil {
+		in, out := &in.Cleanup, &out.Cleanup
+		*out = new(CleanupStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIClusterAutoscalerStatus.
@@ -199,6 +479,16 @@ func (in *OCIClusterAutoscalerStatus) DeepCopy() *OCIClusterAutoscalerStatus {
 func (in *OCIConfig) DeepCopyInto(out *OCIConfig) {
 	*out = *in
 	out.PrivateKeySecretRef = in.PrivateKeySecretRef
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(OCIAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsConfigRef != nil {
+		in, out := &in.CredentialsConfigRef, &out.CredentialsConfigRef
+		*out = new(OCIConfigFileRef)
+		**out = **in
+	}
 	out.Network = in.Network
 }
 
@@ -212,6 +502,21 @@ func (in *OCIConfig) DeepCopy() *OCIConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIConfigFileRef) DeepCopyInto(out *OCIConfigFileRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIConfigFileRef.
+func (in *OCIConfigFileRef) DeepCopy() *OCIConfigFileRef {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIConfigFileRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRequirements) DeepCopyInto(out *ResourceRequirements) {
 	*out = *in
@@ -270,3 +575,24 @@ func (in *ShapeConfig) DeepCopy() *ShapeConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserPrincipalAuth) DeepCopyInto(out *UserPrincipalAuth) {
+	*out = *in
+	out.PrivateKeySecretRef = in.PrivateKeySecretRef
+	if in.PassphraseSecretRef != nil {
+		in, out := &in.PassphraseSecretRef, &out.PassphraseSecretRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserPrincipalAuth.
+func (in *UserPrincipalAuth) DeepCopy() *UserPrincipalAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(UserPrincipalAuth)
+	in.DeepCopyInto(out)
+	return out
+}
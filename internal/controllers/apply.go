@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// fieldManager is the server-side apply field manager owning the fields rendered by the operator
+	fieldManager = "oci-capi-operator"
+
+	// capacityFieldManager owns the size annotations of the managed MachineDeployments, which change
+	// with the capacity schedules independently of the rest of the MachineDeployment
+	capacityFieldManager = "oci-capi-operator-capacity"
+
+	// monitoringFieldManager owns the monitoring label of the managed namespaces
+	monitoringFieldManager = "oci-capi-operator-monitoring"
+
+	// credentialsFieldManager owns the credentials hash annotation of the CAPOCI pod template
+	credentialsFieldManager = "oci-capi-operator-credentials"
+
+	// csrApprovalFieldManager owns the CSRApprovalThrottled condition of the OCIClusterAutoscaler status, which
+	// the CSR approval controller sets independently of the OCIClusterAutoscaler controller
+	csrApprovalFieldManager = "oci-capi-operator-csr-approval"
+)
+
+// legacyFieldManagers are the field managers of the updates made by the operator before it used server-side
+// apply. The controller-runtime client names them after the operator binary.
+var legacyFieldManagers = sets.New("manager")
+
+// apply server-side applies the desired state in obj with the operator field manager. Fields set
+// in obj are enforced, fields previously applied by the operator but missing from obj are removed
+// and fields owned by other managers are left alone. Fields the operator updated before it used
+// server-side apply are handed over to the operator field manager first, so that they are removed
+// as well once they are no longer rendered.
+func (r *OCIClusterAutoscalerReconciler) apply(ctx context.Context, obj client.Object) error {
+	if err := upgradeManagedFields(ctx, r.Client, obj, fieldManager); err != nil {
+		return err
+	}
+	return applyAs(ctx, r.Client, obj, fieldManager)
+}
+
+// upgradeManagedFields moves the fields of the existing object owned by the legacy field managers to the
+// given server-side apply field manager. Nothing is patched once the legacy managers are gone, and objects
+// the cache does not hold yet, such as objects labeled by the first apply, are upgraded on the next apply.
+func upgradeManagedFields(ctx context.Context, c client.Client, obj client.Object, manager string) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	existing, err := emptyObject(c, gvk, obj)
+	if err != nil {
+		return err
+	}
+
+	err = c.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, legacyFieldManagers, manager)
+	if err != nil || patch == nil {
+		return err
+	}
+	if err := c.Patch(ctx, existing, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to upgrade the managed fields of %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+// applyAs server-side applies obj with the given field manager
+func applyAs(ctx context.Context, c client.Client, obj client.Object, manager string) error {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	if err := c.Patch(ctx, obj, client.Apply, client.FieldOwner(manager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
+
+// emptyObject returns an empty object of the same kind and representation as obj
+func emptyObject(c client.Client, gvk schema.GroupVersionKind, obj client.Object) (client.Object, error) {
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u, nil
+	}
+	typed, err := c.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	empty, ok := typed.(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", gvk.Kind)
+	}
+	return empty, nil
+}
+
+// partialObject returns an unstructured object carrying only the identity of an object of the given
+// kind. Typed objects always serialize their required fields, so apply configurations owning only a
+// few fields of an object start from a partial object instead.
+func partialObject(gvk schema.GroupVersionKind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
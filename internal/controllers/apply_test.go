@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// appliedPatch is a server-side apply patch recorded by a fake client
+type appliedPatch struct {
+	obj         client.Object
+	options     client.PatchOptions
+	subResource string
+}
+
+// recordApplyPatches returns interceptor functions recording the server-side apply patches, which the fake
+// client does not support, and passing the other patches through
+func recordApplyPatches(applied *[]appliedPatch) interceptor.Funcs {
+	return interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.Patch(ctx, obj, patch, opts...)
+			}
+			options := client.PatchOptions{}
+			options.ApplyOptions(opts)
+			*applied = append(*applied, appliedPatch{obj: obj.DeepCopyObject().(client.Object), options: options})
+			return nil
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if patch.Type() != types.ApplyPatchType {
+				return c.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+			}
+			options := client.SubResourcePatchOptions{}
+			options.ApplyOptions(opts)
+			*applied = append(*applied, appliedPatch{obj: obj.DeepCopyObject().(client.Object), options: options.PatchOptions, subResource: subResource})
+			return nil
+		},
+	}
+}
+
+var _ = Describe("Server-side apply", func() {
+	It("should apply with the field manager and force ownership", func() {
+		var applied []appliedPatch
+		c := newFakeClientBuilder().WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:            "config",
+			Namespace:       capiSystemNamespace,
+			ResourceVersion: "42",
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "other"}},
+		}}
+		Expect(applyAs(ctx, c, configMap, capacityFieldManager)).To(Succeed())
+
+		Expect(applied).To(HaveLen(1))
+		Expect(applied[0].options.FieldManager).To(Equal(capacityFieldManager))
+		Expect(*applied[0].options.Force).To(BeTrue())
+		Expect(applied[0].obj.GetObjectKind().GroupVersionKind()).To(Equal(corev1.SchemeGroupVersion.WithKind("ConfigMap")))
+		Expect(applied[0].obj.GetResourceVersion()).To(BeEmpty())
+		Expect(applied[0].obj.GetManagedFields()).To(BeEmpty())
+	})
+
+	It("should only serialize the identity of partial objects", func() {
+		obj := partialObject(appsv1.SchemeGroupVersion.WithKind("Deployment"), capociNamespace, capociDeploymentName)
+		data, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(MatchJSON(`{
+			"apiVersion": "apps/v1",
+			"kind": "Deployment",
+			"metadata": {"name": "capoci-controller-manager", "namespace": "cluster-api-provider-oci-system"}
+		}`))
+	})
+
+	Describe("managed fields upgrade", func() {
+		fields := func(paths ...string) *metav1.FieldsV1 {
+			set := map[string]interface{}{}
+			for _, path := range paths {
+				set["f:"+path] = map[string]interface{}{}
+			}
+			raw, err := json.Marshal(map[string]interface{}{"f:data": set})
+			Expect(err).NotTo(HaveOccurred())
+			return &metav1.FieldsV1{Raw: raw}
+		}
+
+		existing := func(managers ...metav1.ManagedFieldsEntry) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: capiSystemNamespace, ManagedFields: managers},
+				Data:       map[string]string{"legacy": "value", "user": "value"},
+			}
+		}
+
+		It("should hand the fields updated by the legacy manager over to the operator", func() {
+			c := newFakeClientBuilder().WithObjects(existing(
+				metav1.ManagedFieldsEntry{Manager: "manager", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: fields("legacy")},
+				metav1.ManagedFieldsEntry{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: fields("user")},
+			)).Build()
+
+			desired := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: capiSystemNamespace}}
+			Expect(upgradeManagedFields(ctx, c, desired, fieldManager)).To(Succeed())
+
+			upgraded := &corev1.ConfigMap{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(desired), upgraded)).To(Succeed())
+			Expect(upgraded.ManagedFields).To(ConsistOf(
+				And(HaveField("Manager", fieldManager), HaveField("Operation", metav1.ManagedFieldsOperationApply)),
+				And(HaveField("Manager", "kubectl-edit"), HaveField("Operation", metav1.ManagedFieldsOperationUpdate)),
+			))
+		})
+
+		It("should leave objects without legacy managers alone", func() {
+			var patched bool
+			c := newFakeClientBuilder().WithObjects(existing(
+				metav1.ManagedFieldsEntry{Manager: fieldManager, Operation: metav1.ManagedFieldsOperationApply, APIVersion: "v1", FieldsType: "FieldsV1", FieldsV1: fields("legacy")},
+			)).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patched = true
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+			}).Build()
+
+			desired := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: capiSystemNamespace}}
+			Expect(upgradeManagedFields(ctx, c, desired, fieldManager)).To(Succeed())
+			Expect(patched).To(BeFalse())
+
+			missing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: capiSystemNamespace}}
+			Expect(upgradeManagedFields(ctx, c, missing, fieldManager)).To(Succeed())
+		})
+	})
+})
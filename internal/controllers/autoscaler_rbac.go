@@ -0,0 +1,242 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// clusterAutoscalerName names the ServiceAccount and Deployment of the cluster-autoscaler, and the
+	// ClusterRole and Role granting it access to the Kubernetes resources
+	clusterAutoscalerName = "oci-cluster-autoscaler"
+
+	// clusterAutoscalerCAPIRoleName is the ClusterRole granting the cluster-autoscaler access to the CAPI resources
+	clusterAutoscalerCAPIRoleName = "oci-cluster-autoscaler-extra"
+
+	// clusterAutoscalerStatusConfigMapName is the ConfigMap the cluster-autoscaler reports its status in
+	clusterAutoscalerStatusConfigMapName = "cluster-autoscaler-status"
+
+	// clusterAutoscalerLeaseName is the leader election Lease of the cluster-autoscaler
+	clusterAutoscalerLeaseName = "cluster-autoscaler"
+)
+
+// The operator grants the cluster-autoscaler permissions it does not hold itself, which requires escalate
+// and bind on the roles it creates. The roles and bindings it creates are the only ones it can change.
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,resourceNames=oci-cluster-autoscaler;oci-cluster-autoscaler-extra;oci-capi-metrics-reader;"system:openshift:scc:oci-capi",verbs=update;patch;delete;escalate;bind
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,resourceNames=oci-cluster-autoscaler;oci-cluster-autoscaler-extra;oci-capi-metrics-reader,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,resourceNames=oci-cluster-autoscaler;prometheus-k8s,verbs=update;patch;delete;escalate;bind
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,resourceNames=oci-cluster-autoscaler;prometheus-k8s;oci-capi-scc,verbs=update;patch;delete
+
+// clusterAutoscalerRules returns the cluster-wide permissions of the cluster-autoscaler on the Kubernetes
+// resources, as granted by the upstream Helm chart, and those of its metrics proxy
+func clusterAutoscalerRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods/eviction"},
+			Verbs:     []string{"create"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods/status"},
+			Verbs:     []string{"update"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"nodes"},
+			Verbs:     []string{"get", "list", "watch", "update", "delete"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"namespaces", "pods", "services", "replicationcontrollers", "persistentvolumeclaims", "persistentvolumes"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"apps"},
+			Resources: []string{"daemonsets", "replicasets", "statefulsets"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"batch"},
+			Resources: []string{"jobs", "cronjobs"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"policy"},
+			Resources: []string{"poddisruptionbudgets"},
+			Verbs:     []string{"list", "watch"},
+		},
+		{
+			APIGroups: []string{"storage.k8s.io"},
+			Resources: []string{"storageclasses", "csinodes", "csidrivers", "csistoragecapacities", "volumeattachments"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		// The metrics proxy authenticates and authorizes the metrics requests
+		{
+			APIGroups: []string{"authentication.k8s.io"},
+			Resources: []string{"tokenreviews"},
+			Verbs:     []string{"create"},
+		},
+		{
+			APIGroups: []string{"authorization.k8s.io"},
+			Resources: []string{"subjectaccessreviews"},
+			Verbs:     []string{"create"},
+		},
+	}
+}
+
+// clusterAutoscalerCAPIRules returns the permissions of the cluster-autoscaler on the CAPI resources: it
+// scales the node groups through their scale subresource, marks the machines to remove and reads the
+// infrastructure templates to scale node groups from zero
+func clusterAutoscalerCAPIRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"cluster.x-k8s.io"},
+			Resources: []string{"machinedeployments", "machinesets", "machinepools", "machines"},
+			Verbs:     []string{"get", "list", "watch", "update"},
+		},
+		{
+			APIGroups: []string{"cluster.x-k8s.io"},
+			Resources: []string{"machinedeployments/scale", "machinesets/scale", "machinepools/scale"},
+			Verbs:     []string{"get", "update"},
+		},
+		{
+			APIGroups: []string{"infrastructure.cluster.x-k8s.io"},
+			Resources: []string{"ocimachinetemplates"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+}
+
+// clusterAutoscalerNamespaceRules returns the permissions of the cluster-autoscaler in its namespace: its
+// status and priority expander ConfigMaps and its leader election Lease
+func clusterAutoscalerNamespaceRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"list", "watch", "create"},
+		},
+		{
+			APIGroups:     []string{""},
+			Resources:     []string{"configmaps"},
+			ResourceNames: []string{clusterAutoscalerStatusConfigMapName, priorityExpanderConfigMapName},
+			Verbs:         []string{"get", "update", "delete"},
+		},
+		{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{"leases"},
+			Verbs:     []string{"create"},
+		},
+		{
+			APIGroups:     []string{"coordination.k8s.io"},
+			Resources:     []string{"leases"},
+			ResourceNames: []string{clusterAutoscalerLeaseName},
+			Verbs:         []string{"get", "update"},
+		},
+	}
+}
+
+// createClusterAutoscalerRBAC grants the cluster-autoscaler ServiceAccount its cluster-wide permissions on
+// the Kubernetes and CAPI resources, and its permissions in its namespace
+func (r *OCIClusterAutoscalerReconciler) createClusterAutoscalerRBAC(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	subjects := []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      clusterAutoscalerName,
+			Namespace: capiSystemNamespace,
+		},
+	}
+
+	for name, rules := range map[string][]rbacv1.PolicyRule{
+		clusterAutoscalerName:         clusterAutoscalerRules(),
+		clusterAutoscalerCAPIRoleName: clusterAutoscalerCAPIRules(),
+	} {
+		clusterRole := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		setOwnerMetadata(clusterRole, autoscaler)
+		clusterRole.Rules = rules
+		if err := r.apply(ctx, clusterRole); err != nil {
+			return fmt.Errorf("failed to create/update ClusterRole %s: %w", name, err)
+		}
+
+		clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+		setOwnerMetadata(clusterRoleBinding, autoscaler)
+		clusterRoleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     name,
+		}
+		clusterRoleBinding.Subjects = subjects
+		if err := r.apply(ctx, clusterRoleBinding); err != nil {
+			return fmt.Errorf("failed to create/update ClusterRoleBinding %s: %w", name, err)
+		}
+	}
+
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterAutoscalerName,
+			Namespace: capiSystemNamespace,
+		},
+	}
+	setOwnerMetadata(role, autoscaler)
+	role.Rules = clusterAutoscalerNamespaceRules()
+	if err := r.apply(ctx, role); err != nil {
+		return fmt.Errorf("failed to create/update Role %s: %w", role.Name, err)
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterAutoscalerName,
+			Namespace: capiSystemNamespace,
+		},
+	}
+	setOwnerMetadata(roleBinding, autoscaler)
+	roleBinding.RoleRef = rbacv1.RoleRef{
+		APIGroup: "rbac.authorization.k8s.io",
+		Kind:     "Role",
+		Name:     clusterAutoscalerName,
+	}
+	roleBinding.Subjects = subjects
+	if err := r.apply(ctx, roleBinding); err != nil {
+		return fmt.Errorf("failed to create/update RoleBinding %s: %w", roleBinding.Name, err)
+	}
+	return nil
+}
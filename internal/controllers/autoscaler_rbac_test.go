@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var _ = Describe("Cluster autoscaler RBAC", func() {
+	allRules := func() []rbacv1.PolicyRule {
+		rules := append(clusterAutoscalerRules(), clusterAutoscalerCAPIRules()...)
+		return append(rules, clusterAutoscalerNamespaceRules()...)
+	}
+
+	It("should not grant wildcards", func() {
+		for _, rule := range allRules() {
+			Expect(rule.APIGroups).NotTo(ContainElement(rbacv1.APIGroupAll))
+			Expect(rule.Resources).NotTo(ContainElement(rbacv1.ResourceAll))
+			Expect(rule.Verbs).NotTo(ContainElement(rbacv1.VerbAll))
+		}
+	})
+
+	It("should scale the CAPI node groups through their scale subresource", func() {
+		Expect(clusterAutoscalerCAPIRules()).To(ContainElement(And(
+			HaveField("Resources", ContainElement("machinedeployments/scale")),
+			HaveField("Verbs", ConsistOf("get", "update")),
+		)))
+	})
+
+	It("should only let the cluster-autoscaler change its own ConfigMaps and Lease", func() {
+		for _, rule := range clusterAutoscalerNamespaceRules() {
+			if len(rule.ResourceNames) == 0 {
+				Expect(rule.Verbs).NotTo(ContainElements("get", "update", "delete"), "%v", rule.Resources)
+			}
+		}
+		Expect(clusterAutoscalerNamespaceRules()).To(ContainElement(And(
+			HaveField("Resources", ConsistOf("leases")),
+			HaveField("ResourceNames", ConsistOf(clusterAutoscalerLeaseName)),
+		)))
+	})
+})
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	ocicapiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
 	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
@@ -10,8 +11,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/go-openapi/swag"
 )
@@ -88,12 +90,12 @@ func (r *OCIClusterAutoscalerReconciler) createCAPIDeployment(ctx context.Contex
 						{
 							Name:  "manager",
 							Image: CAPIManagerImage,
-							Args: []string{
+							Args: withTLSArgs([]string{
 								"--leader-elect",
 								"--diagnostics-address=:8443",
 								"--insecure-diagnostics=false",
 								"--feature-gates=MachinePool=true,ClusterResourceSet=true,ClusterTopology=false,RuntimeSDK=false,MachineSetPreflightChecks=true,MachineWaitForVolumeDetachConsiderVolumeAttachments=true,PriorityQueue=false",
-							},
+							}, r.TLSProfile),
 							Command: []string{
 								"/manager",
 							},
@@ -181,6 +183,11 @@ func (r *OCIClusterAutoscalerReconciler) createCAPIDeployment(ctx context.Contex
 									MountPath: "/tmp/k8s-webhook-server/serving-certs",
 									ReadOnly:  true,
 								},
+								{
+									Name:      "metrics-cert",
+									MountPath: capiManagerMetricsCertDir,
+									ReadOnly:  true,
+								},
 							},
 						},
 					},
@@ -206,93 +213,216 @@ func (r *OCIClusterAutoscalerReconciler) createCAPIDeployment(ctx context.Contex
 								},
 							},
 						},
+						{
+							// Issued by the service CA once monitoring is configured, the manager
+							// generates a self-signed certificate until then
+							Name: "metrics-cert",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName:  capiManagerMetricsCertSecretName,
+									DefaultMode: swag.Int32(420),
+									Optional:    swag.Bool(true),
+								},
+							},
+						},
 					},
 				},
 			},
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
-		return nil
-	})
+	setOwnerMetadata(deploy, instance)
+	err := r.apply(ctx, deploy)
 	if err != nil {
 		return fmt.Errorf("failed to create/update CAPIDeployment: %w", err)
 	}
 	return nil
 }
 
-// activateAutoscalerResources creates or updates all CAPI-related custom resources for the autoscaler
-// This includes:
-// - CAPI OCICluster
-// - CAPI Cluster
-// - CAPI OCIMachineTemplate
-// - CAPI MachineDeployment
-func (r *OCIClusterAutoscalerReconciler) activateAutoscalerResources(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler) error {
+// reconcileCAPICluster creates or updates the OCICluster of the autoscaler, which authenticates through the
+// OCIClusterIdentity of the cluster, and the Cluster referencing it
+func (r *OCIClusterAutoscalerReconciler) reconcileCAPICluster(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler) error {
 	// Create OCICluster
-	err := r.createCAPIOCICluster(ctx, instance)
-	if err != nil {
-		return fmt.Errorf("failed to create CAPI cluster: %w", err)
+	if err := r.createCAPIOCICluster(ctx, instance); err != nil {
+		return fmt.Errorf("failed to create OCICluster: %w", err)
 	}
 
 	// Create Cluster
-	err = r.createCAPICluster(ctx, instance)
-	if err != nil {
+	if err := r.createCAPICluster(ctx, instance); err != nil {
 		return fmt.Errorf("failed to create CAPI cluster: %w", err)
 	}
+	return nil
+}
 
-	// Create OCIMachineTemplate
-	err = r.createOCIMachineTemplate(ctx, instance)
-	if err != nil {
-		return fmt.Errorf("failed to create OCIMachineTemplate: %w", err)
+// reconcileNodeGroups creates or updates the OCIMachineTemplate and the MachineDeployment of every node group,
+// and deletes those of the node groups removed from the spec. The templates of removed node groups are only
+// deleted once their MachineDeployment is gone.
+func (r *OCIClusterAutoscalerReconciler) reconcileNodeGroups(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler) error {
+	groups := sets.New[string]()
+	for _, group := range nodeGroups(instance) {
+		groups.Insert(group.name)
+
+		// Create OCIMachineTemplate
+		if err := r.createOCIMachineTemplate(ctx, instance, group); err != nil {
+			return fmt.Errorf("failed to create OCIMachineTemplate for node group %s: %w", group.name, err)
+		}
+
+		// Create MachineDeployment
+		if err := r.createMachineDeployment(ctx, instance, group); err != nil {
+			return fmt.Errorf("failed to create MachineDeployment for node group %s: %w", group.name, err)
+		}
 	}
 
-	// Create MachineDeployment
-	err = r.createMachineDeployment(ctx, instance)
-	if err != nil {
-		return fmt.Errorf("failed to create MachineDeployment: %w", err)
+	namespace := client.InNamespace(capiNamespace(instance))
+	machineDeployments := &capiv1beta1.MachineDeploymentList{}
+	if err := r.List(ctx, machineDeployments, namespace, ownerLabelSelector(instance)); err != nil {
+		return fmt.Errorf("failed to list MachineDeployments: %w", err)
+	}
+	for i := range machineDeployments.Items {
+		machineDeployment := &machineDeployments.Items[i]
+		if groups.Has(machineDeployment.Name) || !isOwnedBy(machineDeployment, instance) {
+			continue
+		}
+		groups.Insert(machineDeployment.Name)
+		if err := r.Delete(ctx, machineDeployment); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete MachineDeployment %s of a removed node group: %w", machineDeployment.Name, err)
+		}
+	}
+
+	machineTemplates := &infrastructurev1beta2.OCIMachineTemplateList{}
+	if err := r.List(ctx, machineTemplates, namespace, ownerLabelSelector(instance)); err != nil {
+		return fmt.Errorf("failed to list OCIMachineTemplates: %w", err)
+	}
+	for i := range machineTemplates.Items {
+		machineTemplate := &machineTemplates.Items[i]
+		if groups.Has(strings.TrimSuffix(machineTemplate.Name, machineTemplateSuffix)) || !isOwnedBy(machineTemplate, instance) {
+			continue
+		}
+		if err := r.Delete(ctx, machineTemplate); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete OCIMachineTemplate %s of a removed node group: %w", machineTemplate.Name, err)
+		}
 	}
 	return nil
 }
 
+// nodeGroup is a single autoscaling node group backed by an OCIMachineTemplate and a MachineDeployment
+type nodeGroup struct {
+	// name is the name of the MachineDeployment
+	name string
+	// namespace is the namespace of the MachineDeployment
+	namespace   string
+	minNodes    int32
+	maxNodes    int32
+	shape       string
+	shapeConfig *ocicapiv1alpha1.ShapeConfig
+	preemptible bool
+	priority    *int32
+	schedules   []ocicapiv1alpha1.CapacitySchedule
+	// scheduleOverride takes precedence over schedules
+	scheduleOverride *ocicapiv1alpha1.CapacityOverride
+}
+
+// capiNamespace returns the namespace the CAPI cluster objects of the instance are created in
+func capiNamespace(instance *ocicapiv1alpha1.OCIClusterAutoscaler) string {
+	if instance.Spec.CAPI.Namespace != "" {
+		return instance.Spec.CAPI.Namespace
+	}
+	return capiSystemNamespace
+}
+
+// clusterName returns the name of the CAPI cluster of the instance, which CAPI records on the cluster
+// machines in the cluster.x-k8s.io/cluster-name label
+func clusterName(instance *ocicapiv1alpha1.OCIClusterAutoscaler) string {
+	if instance.Spec.CAPI.ClusterName != "" {
+		return instance.Spec.CAPI.ClusterName
+	}
+	return instance.Name
+}
+
+// machineTemplateSuffix is appended to the name of the node group to name its OCIMachineTemplate
+const machineTemplateSuffix = "-autoscaling"
+
+// machineTemplateName returns the name of the OCIMachineTemplate of the node group
+func (g nodeGroup) machineTemplateName() string {
+	return g.name + machineTemplateSuffix
+}
+
+// nodeGroups returns the default node group followed by the additional node groups of the instance
+func nodeGroups(instance *ocicapiv1alpha1.OCIClusterAutoscaler) []nodeGroup {
+	autoscaling := instance.Spec.Autoscaling
+	groups := []nodeGroup{
+		{
+			name:             instance.Name,
+			namespace:        capiNamespace(instance),
+			minNodes:         autoscaling.MinNodes,
+			maxNodes:         autoscaling.MaxNodes,
+			shape:            autoscaling.Shape,
+			shapeConfig:      autoscaling.ShapeConfig,
+			priority:         autoscaling.Priority,
+			schedules:        autoscaling.Schedules,
+			scheduleOverride: autoscaling.ScheduleOverride,
+		},
+	}
+	for _, ng := range autoscaling.NodeGroups {
+		groups = append(groups, nodeGroup{
+			name:             fmt.Sprintf("%s-%s", instance.Name, ng.Name),
+			namespace:        capiNamespace(instance),
+			minNodes:         ng.MinNodes,
+			maxNodes:         ng.MaxNodes,
+			shape:            ng.Shape,
+			shapeConfig:      ng.ShapeConfig,
+			preemptible:      ng.Preemptible,
+			priority:         ng.Priority,
+			schedules:        ng.Schedules,
+			scheduleOverride: ng.ScheduleOverride,
+		})
+	}
+	return groups
+}
+
 func (r *OCIClusterAutoscalerReconciler) createCAPIOCICluster(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler) error { // Create OCICluster
+	if err := r.reconcileClusterIdentity(ctx, instance); err != nil {
+		return err
+	}
+
 	ociCluster := &infrastructurev1beta2.OCICluster{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.Name,
-			Namespace: capiSystemNamespace,
+			Name:      clusterName(instance),
+			Namespace: capiNamespace(instance),
 			Labels: map[string]string{
-				"cluster.x-k8s.io/cluster-name": instance.Name,
+				capiv1beta1.ClusterNameLabel: clusterName(instance),
 			},
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, ociCluster, func() error {
-		ociCluster.Spec = infrastructurev1beta2.OCIClusterSpec{
-			CompartmentId: instance.Spec.OCI.CompartmentID,
-			NetworkSpec: infrastructurev1beta2.NetworkSpec{
-				SkipNetworkManagement: true,
-				Vcn: infrastructurev1beta2.VCN{
-					ID: swag.String(instance.Spec.OCI.Network.VCNID),
-					Subnets: []*infrastructurev1beta2.Subnet{
+	setOwnerMetadata(ociCluster, instance)
+	ociCluster.Spec = infrastructurev1beta2.OCIClusterSpec{
+		CompartmentId: instance.Spec.OCI.CompartmentID,
+		IdentityRef:   clusterIdentityRef(instance),
+		NetworkSpec: infrastructurev1beta2.NetworkSpec{
+			SkipNetworkManagement: true,
+			Vcn: infrastructurev1beta2.VCN{
+				ID: swag.String(instance.Spec.OCI.Network.VCNID),
+				Subnets: []*infrastructurev1beta2.Subnet{
+					{
+						ID:   swag.String(instance.Spec.OCI.Network.SubnetID),
+						Name: "private",
+						Role: "worker",
+					},
+				},
+				NetworkSecurityGroup: infrastructurev1beta2.NetworkSecurityGroup{
+					List: []*infrastructurev1beta2.NSG{
 						{
-							ID:   swag.String(instance.Spec.OCI.Network.SubnetID),
-							Name: "private",
+							ID:   swag.String(instance.Spec.OCI.Network.NetworkSecurityGroupID),
+							Name: "cluster-compute-nsg",
 							Role: "worker",
 						},
 					},
-					NetworkSecurityGroup: infrastructurev1beta2.NetworkSecurityGroup{
-						List: []*infrastructurev1beta2.NSG{
-							{
-								ID:   swag.String(instance.Spec.OCI.Network.NetworkSecurityGroupID),
-								Name: "cluster-compute-nsg",
-								Role: "worker",
-							},
-						},
-					},
 				},
 			},
-		}
-		return nil
-	})
+		},
+	}
+	err := r.apply(ctx, ociCluster)
 	if err != nil {
 		return fmt.Errorf("failed to create/update OCICluster: %w", err)
 	}
@@ -303,106 +433,114 @@ func (r *OCIClusterAutoscalerReconciler) createCAPICluster(ctx context.Context,
 	// Create Cluster
 	cluster := &capiv1beta1.Cluster{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.Name,
-			Namespace: capiSystemNamespace,
+			Name:      clusterName(instance),
+			Namespace: capiNamespace(instance),
 			Labels: map[string]string{
-				"cluster.x-k8s.io/cluster-name": instance.Name,
+				capiv1beta1.ClusterNameLabel: clusterName(instance),
 			},
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, cluster, func() error {
-		cluster.Spec = capiv1beta1.ClusterSpec{
-			ClusterNetwork: &capiv1beta1.ClusterNetwork{
-				Pods: &capiv1beta1.NetworkRanges{
-					CIDRBlocks: []string{"10.128.0.0/14"},
-				},
-				ServiceDomain: "cluster.local",
-				Services: &capiv1beta1.NetworkRanges{
-					CIDRBlocks: []string{"172.30.0.0/16"},
-				},
+	setOwnerMetadata(cluster, instance)
+	cluster.Spec = capiv1beta1.ClusterSpec{
+		ClusterNetwork: &capiv1beta1.ClusterNetwork{
+			Pods: &capiv1beta1.NetworkRanges{
+				CIDRBlocks: []string{"10.128.0.0/14"},
 			},
-			InfrastructureRef: &corev1.ObjectReference{
-				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta2",
-				Kind:       "OCICluster",
-				Name:       instance.Name,
-				Namespace:  capiSystemNamespace,
+			ServiceDomain: "cluster.local",
+			Services: &capiv1beta1.NetworkRanges{
+				CIDRBlocks: []string{"172.30.0.0/16"},
 			},
-		}
-		return nil
-	})
+		},
+		InfrastructureRef: &corev1.ObjectReference{
+			APIVersion: "infrastructure.cluster.x-k8s.io/v1beta2",
+			Kind:       "OCICluster",
+			Name:       clusterName(instance),
+			Namespace:  capiNamespace(instance),
+		},
+	}
+	err := r.apply(ctx, cluster)
 	if err != nil {
 		return fmt.Errorf("failed to create/update Cluster: %w", err)
 	}
 	return nil
 }
 
-func (r *OCIClusterAutoscalerReconciler) createOCIMachineTemplate(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler) error {
+func (r *OCIClusterAutoscalerReconciler) createOCIMachineTemplate(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler, group nodeGroup) error {
 	// Create OCIMachineTemplate
 	machineTemplate := &infrastructurev1beta2.OCIMachineTemplate{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-autoscaling", instance.Name),
-			Namespace: capiSystemNamespace,
+			Name:      group.machineTemplateName(),
+			Namespace: group.namespace,
 		},
 	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, machineTemplate, func() error {
-		machineTemplate.Spec = infrastructurev1beta2.OCIMachineTemplateSpec{
-			Template: infrastructurev1beta2.OCIMachineTemplateResource{
-				Spec: infrastructurev1beta2.OCIMachineSpec{
-					ImageId: instance.Spec.OCI.ImageID,
-					Shape:   instance.Spec.Autoscaling.Shape,
-					ShapeConfig: infrastructurev1beta2.ShapeConfig{
-						Ocpus:       fmt.Sprintf("%d", instance.Spec.Autoscaling.ShapeConfig.CPUs),
-						MemoryInGBs: fmt.Sprintf("%d", instance.Spec.Autoscaling.ShapeConfig.Memory), // TODO: check if this is correct
-					},
-					IsPvEncryptionInTransitEnabled: false,
-				},
+	setOwnerMetadata(machineTemplate, instance)
+	machineSpec := infrastructurev1beta2.OCIMachineSpec{
+		ImageId:                        instance.Spec.OCI.ImageID,
+		Shape:                          group.shape,
+		IsPvEncryptionInTransitEnabled: false,
+	}
+	if group.shapeConfig != nil {
+		machineSpec.ShapeConfig = infrastructurev1beta2.ShapeConfig{
+			Ocpus:       fmt.Sprintf("%d", group.shapeConfig.CPUs),
+			MemoryInGBs: fmt.Sprintf("%d", group.shapeConfig.Memory), // TODO: check if this is correct
+		}
+	}
+	if group.preemptible {
+		machineSpec.PreemptibleInstanceConfig = &infrastructurev1beta2.PreemptibleInstanceConfig{
+			TerminatePreemptionAction: &infrastructurev1beta2.TerminatePreemptionAction{
+				PreserveBootVolume: swag.Bool(false),
 			},
 		}
-		return nil
-	})
+	}
+	machineTemplate.Spec = infrastructurev1beta2.OCIMachineTemplateSpec{
+		Template: infrastructurev1beta2.OCIMachineTemplateResource{
+			Spec: machineSpec,
+		},
+	}
+	err := r.apply(ctx, machineTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to create/update OCIMachineTemplate: %w", err)
 	}
 	return nil
 }
 
-func (r *OCIClusterAutoscalerReconciler) createMachineDeployment(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler) error {
-	// Create MachineDeployment
+func (r *OCIClusterAutoscalerReconciler) createMachineDeployment(ctx context.Context, instance *ocicapiv1alpha1.OCIClusterAutoscaler, group nodeGroup) error {
+	// Create MachineDeployment. Replicas are left to the cluster-autoscaler and the size annotations
+	// of the active capacity schedule are applied separately by reconcileCapacitySchedules.
 	machineDeployment := &capiv1beta1.MachineDeployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.Name,
-			Namespace: capiSystemNamespace,
-			Annotations: map[string]string{
-				"capacity.cluster-autoscaler.kubernetes.io/cpu":               fmt.Sprintf("%d", instance.Spec.Autoscaling.ShapeConfig.CPUs),
-				"capacity.cluster-autoscaler.kubernetes.io/memory":            fmt.Sprintf("%dG", instance.Spec.Autoscaling.ShapeConfig.Memory),
-				"cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size": fmt.Sprintf("%d", instance.Spec.Autoscaling.MinNodes),
-				"cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size": fmt.Sprintf("%d", instance.Spec.Autoscaling.MaxNodes),
-			},
+			Name:      group.name,
+			Namespace: group.namespace,
 		},
 	}
+	if group.shapeConfig != nil {
+		machineDeployment.Annotations = map[string]string{
+			"capacity.cluster-autoscaler.kubernetes.io/cpu":    fmt.Sprintf("%d", group.shapeConfig.CPUs),
+			"capacity.cluster-autoscaler.kubernetes.io/memory": fmt.Sprintf("%dG", group.shapeConfig.Memory),
+		}
+	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, machineDeployment, func() error {
-		machineDeployment.Spec = capiv1beta1.MachineDeploymentSpec{
-			ClusterName: instance.Name,
-			Template: capiv1beta1.MachineTemplateSpec{
-				Spec: capiv1beta1.MachineSpec{
-					ClusterName: instance.Name,
-					Bootstrap: capiv1beta1.Bootstrap{
-						DataSecretName: swag.String(fmt.Sprintf("%s-bootstrap", instance.Name)),
-					},
-					InfrastructureRef: corev1.ObjectReference{
-						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta2",
-						Kind:       "OCIMachineTemplate",
-						Name:       fmt.Sprintf("%s-autoscaling", instance.Name),
-						Namespace:  capiSystemNamespace,
-					},
+	setOwnerMetadata(machineDeployment, instance)
+	machineDeployment.Spec = capiv1beta1.MachineDeploymentSpec{
+		ClusterName: clusterName(instance),
+		Template: capiv1beta1.MachineTemplateSpec{
+			Spec: capiv1beta1.MachineSpec{
+				ClusterName: clusterName(instance),
+				Bootstrap: capiv1beta1.Bootstrap{
+					DataSecretName: swag.String(fmt.Sprintf("%s-bootstrap", instance.Name)),
+				},
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: "infrastructure.cluster.x-k8s.io/v1beta2",
+					Kind:       "OCIMachineTemplate",
+					Name:       group.machineTemplateName(),
+					Namespace:  group.namespace,
 				},
 			},
-		}
-		return nil
-	})
+		},
+	}
+	err := r.apply(ctx, machineDeployment)
 	if err != nil {
 		return fmt.Errorf("failed to create/update MachineDeployment: %w", err)
 	}
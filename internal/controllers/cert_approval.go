@@ -18,31 +18,67 @@ package controllers
 
 import (
 	"context"
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // CertificateApprovalReconciler reconciles CertificateSigningRequests for OCI machines
 type CertificateApprovalReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// identityIndexed is true when the OCIMachines could be indexed by identity, which requires the
+	// OCIMachine API to be served when the operator starts
+	identityIndexed bool
 }
 
+const (
+	// ociMachineIdentityIndex indexes OCIMachines by the lowercased names their node can register with
+	ociMachineIdentityIndex = "ocimachine.identity"
+
+	// pendingCSRIdentityIndex indexes the pending kubelet CSRs by the lowercased node name they were
+	// requested for and, for serving certificates, by their DNS and IP SANs
+	pendingCSRIdentityIndex = "csr.pending.identity"
+
+	// pendingCSRExpiry is the age after which kube-controller-manager garbage collects pending CSRs
+	pendingCSRExpiry = 24 * time.Hour
+
+	// Bounds of the delay between rechecks of CSRs that could not be approved yet
+	minPendingCSRRecheck = 30 * time.Second
+	maxPendingCSRRecheck = 10 * time.Minute
+
+	// Reasons of the events recording the decision taken for a CSR
+	csrApprovedEventReason = "CSRApproved"
+	csrDeniedEventReason   = "CSRDenied"
+	csrIgnoredEventReason  = "CSRIgnored"
+)
+
 // +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests/approval,verbs=update
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=signers,resourceNames=kubernetes.io/kube-apiserver-client-kubelet;kubernetes.io/kubelet-serving,verbs=approve
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ocimachines,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile handles certificate approval for OCI machines
 func (r *CertificateApprovalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -69,77 +105,311 @@ func (r *CertificateApprovalReconciler) Reconcile(ctx context.Context, req ctrl.
 	hostname, err := getCSRHostname(csr)
 	if err != nil {
 		logger.Error(err, "Failed to extract hostname from CSR", "csr", csr.Name, "signerName", csr.Spec.SignerName)
+		r.recordDecision(csr, nil, corev1.EventTypeWarning, csrIgnoredEventReason, "Ignored: %v", err)
 		return ctrl.Result{}, nil
 	}
 
 	if hostname == "" {
 		logger.V(1).Info("No hostname found in CSR", "csr", csr.Name, "signerName", csr.Spec.SignerName)
+		r.recordDecision(csr, nil, corev1.EventTypeWarning, csrIgnoredEventReason, "Ignored: no hostname found")
+		return ctrl.Result{}, nil
+	}
+
+	// Check if there's exactly one matching OCIMachine in the served clusters
+	machine, autoscaler, err := r.findMatchingOCIMachine(ctx, hostname)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if machine == nil {
+		r.recordDecision(csr, nil, corev1.EventTypeNormal, csrIgnoredEventReason, "Ignored: node %s does not match exactly one OCIMachine of the managed clusters", hostname)
+		return ctrl.Result{RequeueAfter: pendingCSRRecheck(csr, time.Now())}, nil
+	}
+
+	// Leave the CSR alone when approval is disabled or delegated for the cluster
+	switch mode := csrApproverMode(autoscaler); mode {
+	case capiv1alpha1.CSRApproverModeDisabled:
+		r.recordDecision(csr, machine, corev1.EventTypeNormal, csrIgnoredEventReason, "Ignored CSR %s for node %s: approval is disabled", csr.Name, hostname)
+		return ctrl.Result{}, nil
+	case capiv1alpha1.CSRApproverModeDelegateToMachineApprover:
+		state, err := detectMachineApprover(ctx, r.Client)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !state.approvesCAPIMachines(machine.Namespace) {
+			logger.Info("Approval is delegated but no available cluster-machine-approver approves CAPI machines of the namespace", "csr", csr.Name, "hostname", hostname, "machine", machine.Name)
+		}
+		r.recordDecision(csr, machine, corev1.EventTypeNormal, csrIgnoredEventReason, "Ignored CSR %s for node %s: approval is delegated to the cluster-machine-approver", csr.Name, hostname)
 		return ctrl.Result{}, nil
 	}
 
-	// Check if there's a matching OCIMachine
-	if r.hasMatchingOCIMachine(ctx, hostname) {
-		logger.Info("Approving certificate for OCI machine", "csr", csr.Name, "hostname", hostname)
+	// Client certificate renewals of joined nodes are approved by kube-controller-manager
+	if csr.Spec.SignerName == kubeletClientSignerName && csr.Spec.Username == nodeUserPrefix+hostname {
+		logger.V(1).Info("Leaving client certificate renewal to kube-controller-manager", "csr", csr.Name, "hostname", hostname)
+		return ctrl.Result{}, nil
+	}
 
-		// Approve the CSR
-		now := metav1.NewTime(time.Now())
-		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
-			Type:               certificatesv1.CertificateApproved,
-			Status:             corev1.ConditionTrue,
-			Reason:             "OCIMachineApproval",
-			Message:            "Approved by OCI CAPI operator for matching OCIMachine",
-			LastUpdateTime:     now,
-			LastTransitionTime: now,
-		})
+	// Validate the request against the identity of the machine
+	validation, err := r.validateCSR(ctx, csr, hostname, machine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	invalid := validation.invalid
+	if invalid != nil {
+		if autoscaler == nil || !autoscaler.Spec.CSRApproval.DenyInvalid || !isDeniable(invalid) {
+			logger.Info("Declining CSR failing validation", "csr", csr.Name, "hostname", hostname, "machine", machine.Name, "reason", invalid.Error())
+			r.recordDecision(csr, machine, corev1.EventTypeWarning, csrIgnoredEventReason, "Ignored CSR %s for node %s: %v", csr.Name, hostname, invalid)
+			if isDeniable(invalid) {
+				return ctrl.Result{}, nil
+			}
+			// The node or machine may still catch up with the CSR
+			return ctrl.Result{RequeueAfter: pendingCSRRecheck(csr, time.Now())}, nil
+		}
 
-		if err := r.Status().Update(ctx, csr); err != nil {
+		logger.Info("Denying CSR failing validation", "csr", csr.Name, "hostname", hostname, "machine", machine.Name, "reason", invalid.Error())
+		if err := r.updateApproval(ctx, csr, certificatesv1.CertificateDenied, csrApprovalReasonPrefix+csrValidationReason(invalid), denialMessage(machine, invalid)); err != nil {
 			return ctrl.Result{}, err
 		}
+		r.recordDecision(csr, machine, corev1.EventTypeWarning, csrDeniedEventReason, "Denied CSR %s for node %s: %v", csr.Name, hostname, invalid)
+		return ctrl.Result{}, nil
 	}
 
+	// Pause approval while the pending or per machine limits are exceeded
+	throttled, err := r.throttleApproval(ctx, autoscaler, machine)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if throttled {
+		logger.Info("Not approving CSR while approval limits are exceeded", "csr", csr.Name, "hostname", hostname, "machine", machine.Name)
+		r.recordDecision(csr, machine, corev1.EventTypeWarning, csrIgnoredEventReason, "Ignored CSR %s for node %s while approval limits are exceeded", csr.Name, hostname)
+		return ctrl.Result{RequeueAfter: throttledApprovalRetryDelay}, nil
+	}
+
+	request := csrRequestInitial
+	if validation.renewal {
+		request = csrRequestRenewal
+	}
+	logger.Info("Approving certificate for OCI machine", "csr", csr.Name, "hostname", hostname, "machine", machine.Name, "request", request)
+
+	// Approve the CSR
+	if err := r.updateApproval(ctx, csr, certificatesv1.CertificateApproved, csrApprovedConditionReason, approvalMessage(machine)); err != nil {
+		return ctrl.Result{}, err
+	}
+	csrApprovalsTotal.WithLabelValues(csr.Spec.SignerName, request).Inc()
+	r.recordDecision(csr, machine, corev1.EventTypeNormal, csrApprovedEventReason, "Approved %s CSR %s for node %s", request, csr.Name, hostname)
+
 	return ctrl.Result{}, nil
 }
 
+// updateApproval approves or denies the CSR through its approval subresource
+func (r *CertificateApprovalReconciler) updateApproval(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, conditionType certificatesv1.RequestConditionType, reason, message string) error {
+	now := metav1.NewTime(time.Now())
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+	})
+
+	if err := r.SubResource("approval").Update(ctx, csr); err != nil {
+		return fmt.Errorf("failed to set %s condition on CSR %s: %w", conditionType, csr.Name, err)
+	}
+	return nil
+}
+
+// recordDecision records an event for the decision taken on the CSR, on the CSR itself and on the
+// OCIMachine it was matched to, if any
+func (r *CertificateApprovalReconciler) recordDecision(csr *certificatesv1.CertificateSigningRequest, machine *infrastructurev1beta2.OCIMachine, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(csr, eventType, reason, messageFmt, args...)
+	if machine != nil {
+		r.Recorder.Eventf(machine, eventType, reason, messageFmt, args...)
+	}
+}
+
+// approvalMessage returns the message of the approval condition of CSRs approved for the machine
+func approvalMessage(machine *infrastructurev1beta2.OCIMachine) string {
+	return fmt.Sprintf("Approved by %s for OCIMachine %s/%s", csrApproverIdentity, machine.Namespace, machine.Name)
+}
+
+// denialMessage returns the message of the denial condition of CSRs of the machine failing validation
+func denialMessage(machine *infrastructurev1beta2.OCIMachine, invalid error) string {
+	return fmt.Sprintf("Denied by %s for OCIMachine %s/%s: %v", csrApproverIdentity, machine.Namespace, machine.Name, invalid)
+}
+
 func isKubeletCSR(csr *certificatesv1.CertificateSigningRequest) bool {
-	return csr.Spec.SignerName == "kubernetes.io/kube-apiserver-client-kubelet" ||
-		csr.Spec.SignerName == "kubernetes.io/kubelet-serving"
+	return csr.Spec.SignerName == kubeletClientSignerName ||
+		csr.Spec.SignerName == kubeletServingSignerName
+}
+
+// clusterScope identifies the machines of a CAPI cluster: the namespace they are in and the value of
+// their cluster.x-k8s.io/cluster-name label
+type clusterScope struct {
+	namespace string
+	cluster   string
+}
+
+// machineScope returns the scope of the CAPI cluster the machine belongs to
+func machineScope(machine client.Object) clusterScope {
+	return clusterScope{namespace: machine.GetNamespace(), cluster: machine.GetLabels()[capiv1beta1.ClusterNameLabel]}
+}
+
+// servedClusters returns the CAPI clusters of the OCIClusterAutoscalers, which are the only clusters
+// whose machines get their CSRs approved
+func (r *CertificateApprovalReconciler) servedClusters(ctx context.Context) (map[clusterScope]*capiv1alpha1.OCIClusterAutoscaler, error) {
+	autoscalers := &capiv1alpha1.OCIClusterAutoscalerList{}
+	if err := r.List(ctx, autoscalers); err != nil {
+		return nil, fmt.Errorf("failed to list OCIClusterAutoscalers: %w", err)
+	}
+
+	served := make(map[clusterScope]*capiv1alpha1.OCIClusterAutoscaler, len(autoscalers.Items))
+	for i := range autoscalers.Items {
+		autoscaler := &autoscalers.Items[i]
+		served[clusterScope{namespace: capiNamespace(autoscaler), cluster: clusterName(autoscaler)}] = autoscaler
+	}
+	return served, nil
 }
 
-func (r *CertificateApprovalReconciler) hasMatchingOCIMachine(ctx context.Context, hostname string) bool {
+// findMatchingOCIMachine returns the OCIMachine the node with the given name belongs to, along with the
+// OCIClusterAutoscaler serving its cluster. Only machines of the served clusters are considered and nil
+// is returned when no machine or more than one machine matches, so that ambiguous CSRs are never approved.
+func (r *CertificateApprovalReconciler) findMatchingOCIMachine(ctx context.Context, hostname string) (*infrastructurev1beta2.OCIMachine, *capiv1alpha1.OCIClusterAutoscaler, error) {
 	logger := log.FromContext(ctx)
 
-	// List OCIMachines to find a match
-	machineList := &metav1.PartialObjectMetadataList{}
-	machineList.SetGroupVersionKind(schema.GroupVersionKind{
-		Group:   "infrastructure.cluster.x-k8s.io",
-		Version: "v1beta2",
-		Kind:    "OCIMachine",
-	})
+	served, err := r.servedClusters(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(served) == 0 {
+		logger.V(1).Info("No OCIClusterAutoscaler to approve CSRs for", "hostname", hostname)
+		return nil, nil, nil
+	}
 
-	err := r.List(ctx, machineList, client.InNamespace("capi-system"))
+	machines, err := r.listOCIMachines(ctx, hostname, served)
 	if err != nil {
-		logger.Error(err, "Failed to list OCIMachines")
-		return false
+		return nil, nil, fmt.Errorf("failed to list OCIMachines: %w", err)
 	}
 
-	for _, machine := range machineList.Items {
-		// More precise matching - look for hostname as prefix or suffix of machine name
-		// This handles cases like hostname="worker-1" and machine.Name="my-cluster-worker-1"
-		if strings.Contains(machine.Name, hostname) || strings.Contains(hostname, machine.Name) {
-			logger.V(1).Info("Found matching OCIMachine", "hostname", hostname, "machine", machine.Name)
-			return true
+	var candidates []infrastructurev1beta2.OCIMachine
+	for _, machine := range machines {
+		if _, ok := served[machineScope(&machine)]; ok {
+			candidates = append(candidates, machine)
 		}
 	}
 
-	logger.V(1).Info("No matching OCIMachine found", "hostname", hostname, "machineCount", len(machineList.Items))
-	return false
+	matches := matchOCIMachines(candidates, hostname)
+	switch len(matches) {
+	case 0:
+		logger.V(1).Info("No matching OCIMachine found", "hostname", hostname, "unservedMachineCount", len(machines)-len(candidates))
+		return nil, nil, nil
+	case 1:
+		logger.V(1).Info("Found matching OCIMachine", "hostname", hostname, "machine", matches[0].Name)
+		return matches[0], served[machineScope(matches[0])], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, machine := range matches {
+			names = append(names, client.ObjectKeyFromObject(machine).String())
+		}
+		logger.Info("Declining CSR matching more than one OCIMachine", "hostname", hostname, "machines", names)
+		return nil, nil, nil
+	}
+}
+
+// listOCIMachines returns the OCIMachines the node with the given name may belong to: the OCIMachines indexed
+// under the name or, when the index could not be registered at startup, all the OCIMachines of the served
+// namespaces
+func (r *CertificateApprovalReconciler) listOCIMachines(ctx context.Context, hostname string, served map[clusterScope]*capiv1alpha1.OCIClusterAutoscaler) ([]infrastructurev1beta2.OCIMachine, error) {
+	if r.identityIndexed {
+		machineList := &infrastructurev1beta2.OCIMachineList{}
+		if err := r.List(ctx, machineList, client.MatchingFields{ociMachineIdentityIndex: strings.ToLower(hostname)}); err != nil {
+			return nil, err
+		}
+		return machineList.Items, nil
+	}
+
+	namespaces := sets.New[string]()
+	for scope := range served {
+		namespaces.Insert(scope.namespace)
+	}
+	var machines []infrastructurev1beta2.OCIMachine
+	for _, namespace := range sets.List(namespaces) {
+		machineList := &infrastructurev1beta2.OCIMachineList{}
+		if err := r.List(ctx, machineList, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		machines = append(machines, machineList.Items...)
+	}
+	return machines, nil
+}
+
+// indexOCIMachineIdentity is the indexer function for ociMachineIdentityIndex
+func indexOCIMachineIdentity(obj client.Object) []string {
+	machine, ok := obj.(*infrastructurev1beta2.OCIMachine)
+	if !ok {
+		return nil
+	}
+
+	var identities []string
+	for _, identity := range ociMachineIdentities(machine) {
+		identity = strings.ToLower(identity)
+		if !slices.Contains(identities, identity) {
+			identities = append(identities, identity)
+		}
+	}
+	return identities
+}
+
+// matchOCIMachines returns the machines having the node name among their identities
+func matchOCIMachines(machines []infrastructurev1beta2.OCIMachine, hostname string) []*infrastructurev1beta2.OCIMachine {
+	var matches []*infrastructurev1beta2.OCIMachine
+	for i := range machines {
+		for _, identity := range ociMachineIdentities(&machines[i]) {
+			if strings.EqualFold(identity, hostname) {
+				matches = append(matches, &machines[i])
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// ociMachineIdentities returns the names a node backed by the OCIMachine can register with: the
+// OCIMachine name, which CAPOCI also uses as the instance display name, the name of the owning CAPI
+// Machine, the instance OCID and the hostnames and IPs reported for the instance
+func ociMachineIdentities(machine *infrastructurev1beta2.OCIMachine) []string {
+	identities := []string{machine.Name}
+
+	for _, owner := range machine.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err == nil && gv.Group == capiv1beta1.GroupVersion.Group && owner.Kind == "Machine" {
+			identities = append(identities, owner.Name)
+		}
+	}
+
+	if machine.Spec.InstanceId != nil && *machine.Spec.InstanceId != "" {
+		identities = append(identities, *machine.Spec.InstanceId)
+	}
+	if machine.Spec.ProviderID != nil && *machine.Spec.ProviderID != "" {
+		identities = append(identities, strings.TrimPrefix(*machine.Spec.ProviderID, "oci://"))
+	}
+
+	for _, address := range machine.Status.Addresses {
+		if address.Address != "" {
+			identities = append(identities, address.Address)
+		}
+	}
+
+	return identities
 }
 
 func getCSRHostname(csr *certificatesv1.CertificateSigningRequest) (string, error) {
 	switch csr.Spec.SignerName {
-	case "kubernetes.io/kube-apiserver-client-kubelet":
+	case kubeletClientSignerName:
 		return getHostnameFromClientKubeletCSR(csr)
-	case "kubernetes.io/kubelet-serving":
+	case kubeletServingSignerName:
 		return getHostnameFromServingCSR(csr)
 	default:
 		return "", nil
@@ -147,18 +417,9 @@ func getCSRHostname(csr *certificatesv1.CertificateSigningRequest) (string, erro
 }
 
 func getHostnameFromClientKubeletCSR(csr *certificatesv1.CertificateSigningRequest) (string, error) {
-	if len(csr.Spec.Request) == 0 {
-		return "", fmt.Errorf("CSR request is empty")
-	}
-
-	block, _ := pem.Decode(csr.Spec.Request)
-	if block == nil || block.Type != "CERTIFICATE REQUEST" {
-		return "", fmt.Errorf("failed to decode PEM block or invalid block type")
-	}
-
-	certReq, err := x509.ParseCertificateRequest(block.Bytes)
+	certReq, err := parseCSR(csr)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse certificate request: %w", err)
+		return "", err
 	}
 
 	// Extract CN from subject
@@ -167,12 +428,9 @@ func getHostnameFromClientKubeletCSR(csr *certificatesv1.CertificateSigningReque
 		return "", fmt.Errorf("certificate request has empty CommonName")
 	}
 
-	if strings.HasPrefix(subject, "system:node:") {
-		hostname := strings.TrimPrefix(subject, "system:node:")
-		// Remove domain suffix if present
-		if dotIndex := strings.Index(hostname, "."); dotIndex > 0 {
-			hostname = hostname[:dotIndex]
-		}
+	if strings.HasPrefix(subject, nodeUserPrefix) {
+		// The node name is kept whole, including any domain, so that it can be matched exactly
+		hostname := strings.TrimPrefix(subject, nodeUserPrefix)
 		if hostname == "" {
 			return "", fmt.Errorf("extracted hostname is empty")
 		}
@@ -190,11 +448,8 @@ func getHostnameFromServingCSR(csr *certificatesv1.CertificateSigningRequest) (s
 	}
 
 	// The username should be in the format system:node:hostname
-	if strings.HasPrefix(username, "system:node:") {
-		hostname := strings.TrimPrefix(username, "system:node:")
-		if dotIndex := strings.Index(hostname, "."); dotIndex > 0 {
-			hostname = hostname[:dotIndex]
-		}
+	if strings.HasPrefix(username, nodeUserPrefix) {
+		hostname := strings.TrimPrefix(username, nodeUserPrefix)
 		if hostname == "" {
 			return "", fmt.Errorf("extracted hostname is empty")
 		}
@@ -222,11 +477,124 @@ func isCSRDenied(csr *certificatesv1.CertificateSigningRequest) bool {
 	return false
 }
 
+// pendingCSRRecheck returns when a CSR that could not be approved yet should be looked at again. The
+// delay grows with the age of the CSR and rechecks stop once the CSR is old enough to be garbage
+// collected by kube-controller-manager, in which case 0 is returned.
+func pendingCSRRecheck(csr *certificatesv1.CertificateSigningRequest, now time.Time) time.Duration {
+	age := now.Sub(csr.CreationTimestamp.Time)
+	if age >= pendingCSRExpiry {
+		return 0
+	}
+	return min(max(age/10, minPendingCSRRecheck), maxPendingCSRRecheck)
+}
+
+// requestsForMachineCSRs maps an OCIMachine or a CAPI Machine to the pending kubelet CSRs requested under one
+// of its identities, so that CSRs created before their machine was known are approved as soon as it shows up
+func (r *CertificateApprovalReconciler) requestsForMachineCSRs(ctx context.Context, obj client.Object) []reconcile.Request {
+	var identities []string
+	switch machine := obj.(type) {
+	case *infrastructurev1beta2.OCIMachine:
+		identities = ociMachineIdentities(machine)
+	case *capiv1beta1.Machine:
+		identities = capiMachineIdentities(machine)
+	default:
+		return nil
+	}
+
+	names := sets.New[string]()
+	for _, identity := range identities {
+		csrs := &certificatesv1.CertificateSigningRequestList{}
+		if err := r.List(ctx, csrs, client.MatchingFields{pendingCSRIdentityIndex: strings.ToLower(identity)}); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list CSRs for machine", "machine", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+		for _, csr := range csrs.Items {
+			names.Insert(csr.Name)
+		}
+	}
+
+	requests := make([]reconcile.Request, 0, names.Len())
+	for _, name := range sets.List(names) {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return requests
+}
+
+// indexPendingCSRIdentity is the indexer function for pendingCSRIdentityIndex. Approved and denied CSRs
+// are not indexed.
+func indexPendingCSRIdentity(obj client.Object) []string {
+	csr, ok := obj.(*certificatesv1.CertificateSigningRequest)
+	if !ok || !isKubeletCSR(csr) || isCSRApproved(csr) || isCSRDenied(csr) {
+		return nil
+	}
+
+	identities := sets.New[string]()
+	if hostname, err := getCSRHostname(csr); err == nil && hostname != "" {
+		identities.Insert(strings.ToLower(hostname))
+	}
+	if csr.Spec.SignerName == kubeletServingSignerName {
+		if certReq, err := parseCSR(csr); err == nil {
+			for _, name := range certReq.DNSNames {
+				identities.Insert(strings.ToLower(name))
+			}
+			for _, ip := range certReq.IPAddresses {
+				identities.Insert(ip.String())
+			}
+		}
+	}
+	return sets.List(identities)
+}
+
+// capiMachineIdentities returns the names a node backed by the CAPI Machine can register with
+func capiMachineIdentities(machine *capiv1beta1.Machine) []string {
+	identities := []string{machine.Name, machine.Spec.InfrastructureRef.Name}
+	if machine.Spec.ProviderID != nil && *machine.Spec.ProviderID != "" {
+		identities = append(identities, strings.TrimPrefix(*machine.Spec.ProviderID, "oci://"))
+	}
+	for _, address := range machine.Status.Addresses {
+		if address.Address != "" {
+			identities = append(identities, address.Address)
+		}
+	}
+	return identities
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *CertificateApprovalReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&certificatesv1.CertificateSigningRequest{}).
-		Complete(r)
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &certificatesv1.CertificateSigningRequest{},
+		pendingCSRIdentityIndex, indexPendingCSRIdentity); err != nil {
+		return err
+	}
+
+	// The index can only be registered while the OCIMachine API is served. When CAPOCI is installed after
+	// the operator started, OCIMachines are matched by listing the served namespaces instead.
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &infrastructurev1beta2.OCIMachine{},
+		ociMachineIdentityIndex, indexOCIMachineIdentity)
+	switch {
+	case meta.IsNoMatchError(err):
+		mgr.GetLogger().Info("OCIMachine API not installed, matching CSRs without the OCIMachine identity index")
+	case err != nil:
+		return err
+	default:
+		r.identityIndexed = true
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&certificatesv1.CertificateSigningRequest{})
+
+	// Machines are watched when their APIs are installed
+	for _, obj := range []client.Object{&infrastructurev1beta2.OCIMachine{}, &capiv1beta1.Machine{}} {
+		installed, err := isKindInstalled(mgr, obj)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			continue
+		}
+		b = b.Watches(obj, handler.EnqueueRequestsFromMapFunc(r.requestsForMachineCSRs))
+	}
+
+	return b.Complete(r)
 }
 
 // deployCertificateApproval creates a deployment that handles certificate approval
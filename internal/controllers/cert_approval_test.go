@@ -0,0 +1,572 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/go-openapi/swag"
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+)
+
+var _ = Describe("CSR to OCIMachine matching", func() {
+	var machines []infrastructurev1beta2.OCIMachine
+
+	BeforeEach(func() {
+		machines = []infrastructurev1beta2.OCIMachine{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "workers-abcde",
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: capiv1beta1.GroupVersion.String(), Kind: "Machine", Name: "workers-7d9f-abcde"},
+					},
+				},
+				Spec: infrastructurev1beta2.OCIMachineSpec{
+					InstanceId: swag.String("ocid1.instance.oc1.phx.aaaa"),
+					ProviderID: swag.String("oci://ocid1.instance.oc1.phx.aaaa"),
+				},
+				Status: infrastructurev1beta2.OCIMachineStatus{
+					Addresses: []capiv1beta1.MachineAddress{
+						{Type: capiv1beta1.MachineInternalIP, Address: "10.0.1.15"},
+						{Type: capiv1beta1.MachineInternalDNS, Address: "worker-1.subnet.vcn.oraclevcn.com"},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "workers-fghij"},
+			},
+		}
+	})
+
+	It("should match the OCIMachine name", func() {
+		matches := matchOCIMachines(machines, "workers-abcde")
+		Expect(matches).To(HaveLen(1))
+		Expect(matches[0].Name).To(Equal("workers-abcde"))
+	})
+
+	It("should match the owning Machine, the instance OCID and the instance addresses", func() {
+		for _, hostname := range []string{"workers-7d9f-abcde", "ocid1.instance.oc1.phx.aaaa", "10.0.1.15", "worker-1.subnet.vcn.oraclevcn.com"} {
+			matches := matchOCIMachines(machines, hostname)
+			Expect(matches).To(HaveLen(1), hostname)
+			Expect(matches[0].Name).To(Equal("workers-abcde"), hostname)
+		}
+	})
+
+	It("should not match on substrings of machine names", func() {
+		for _, hostname := range []string{"workers", "abcde", "1", "worker-1", "workers-abcde-2"} {
+			Expect(matchOCIMachines(machines, hostname)).To(BeEmpty(), hostname)
+		}
+	})
+
+	It("should index machines by their lowercased identities", func() {
+		machines[0].Status.Addresses = append(machines[0].Status.Addresses, capiv1beta1.MachineAddress{Type: capiv1beta1.MachineHostName, Address: "Workers-ABCDE"})
+		Expect(indexOCIMachineIdentity(&machines[0])).To(ConsistOf(
+			"workers-abcde", "workers-7d9f-abcde", "ocid1.instance.oc1.phx.aaaa", "10.0.1.15", "worker-1.subnet.vcn.oraclevcn.com",
+		))
+	})
+
+	It("should scope machines to the CAPI cluster of their OCIClusterAutoscaler", func() {
+		autoscaler := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "default"}}
+		Expect(clusterScope{namespace: capiNamespace(autoscaler), cluster: clusterName(autoscaler)}).To(Equal(clusterScope{namespace: capiSystemNamespace, cluster: "workers"}))
+
+		autoscaler.Spec.CAPI = capiv1alpha1.CAPIConfig{Namespace: "capi-workers", ClusterName: "oci"}
+		machine := &infrastructurev1beta2.OCIMachine{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "capi-workers",
+			Labels:    map[string]string{capiv1beta1.ClusterNameLabel: "oci"},
+		}}
+		Expect(machineScope(machine)).To(Equal(clusterScope{namespace: capiNamespace(autoscaler), cluster: clusterName(autoscaler)}))
+	})
+
+	It("should report every machine sharing an identity", func() {
+		machines[1].Status.Addresses = []capiv1beta1.MachineAddress{
+			{Type: capiv1beta1.MachineInternalIP, Address: "10.0.1.15"},
+		}
+		Expect(matchOCIMachines(machines, "10.0.1.15")).To(HaveLen(2))
+	})
+
+	It("should list the machines of the served namespaces without the identity index", func() {
+		machines[0].Namespace = capiSystemNamespace
+		machines[1].Namespace = "unserved"
+		c := newFakeClientBuilder().WithObjects(&machines[0], &machines[1]).Build()
+		r := &CertificateApprovalReconciler{Client: c}
+
+		served := map[clusterScope]*capiv1alpha1.OCIClusterAutoscaler{{namespace: capiSystemNamespace, cluster: "workers"}: {}}
+		listed, err := r.listOCIMachines(ctx, "workers-abcde", served)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(listed).To(HaveLen(1))
+		Expect(listed[0].Name).To(Equal("workers-abcde"))
+	})
+})
+
+// newTestCSR returns a CSR for the given subject and subject alternative names
+func newTestCSR(signerName, username string, groups []string, usages []certificatesv1.KeyUsage, subject pkix.Name, dnsNames []string, ips []net.IP) *certificatesv1.CertificateSigningRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:     subject,
+		DNSNames:    dnsNames,
+		IPAddresses: ips,
+	}, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "csr-test"},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName: signerName,
+			Username:   username,
+			Groups:     groups,
+			Usages:     usages,
+		},
+	}
+}
+
+var _ = Describe("Kubelet serving CSR validation", func() {
+	const hostname = "worker-1"
+	var (
+		machine *infrastructurev1beta2.OCIMachine
+		node    *corev1.Node
+		usages  []certificatesv1.KeyUsage
+		subject pkix.Name
+	)
+
+	BeforeEach(func() {
+		machine = &infrastructurev1beta2.OCIMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-abcde"},
+			Spec: infrastructurev1beta2.OCIMachineSpec{
+				ProviderID: swag.String("oci://ocid1.instance.oc1.phx.aaaa"),
+			},
+			Status: infrastructurev1beta2.OCIMachineStatus{
+				Addresses: []capiv1beta1.MachineAddress{
+					{Type: capiv1beta1.MachineInternalIP, Address: "10.0.1.15"},
+					{Type: capiv1beta1.MachineInternalDNS, Address: "worker-1.subnet.vcn.oraclevcn.com"},
+				},
+			},
+		}
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: hostname},
+			Spec:       corev1.NodeSpec{ProviderID: "oci://ocid1.instance.oc1.phx.aaaa"},
+		}
+		usages = []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth}
+		subject = pkix.Name{CommonName: nodeUserPrefix + hostname, Organization: []string{nodesGroup}}
+	})
+
+	servingCSR := func(dnsNames []string, ips []net.IP) *certificatesv1.CertificateSigningRequest {
+		return newTestCSR(kubeletServingSignerName, nodeUserPrefix+hostname, []string{nodesGroup, "system:authenticated"}, usages, subject, dnsNames, ips)
+	}
+
+	It("should accept a CSR naming the node and its instance addresses", func() {
+		csr := servingCSR([]string{hostname, "worker-1.subnet.vcn.oraclevcn.com"}, []net.IP{net.ParseIP("10.0.1.15")})
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(Succeed())
+	})
+
+	It("should reject subject alternative names outside the instance addresses", func() {
+		csr := servingCSR([]string{hostname, "kubernetes.default.svc"}, nil)
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(MatchError(ContainSubstring("kubernetes.default.svc")))
+
+		csr = servingCSR([]string{hostname}, []net.IP{net.ParseIP("10.0.1.16")})
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(MatchError(ContainSubstring("10.0.1.16")))
+	})
+
+	It("should reject usages a serving certificate does not need", func() {
+		usages = append(usages, certificatesv1.UsageClientAuth)
+		csr := servingCSR([]string{hostname}, nil)
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(MatchError(ContainSubstring("client auth")))
+	})
+
+	It("should reject requesters outside the nodes group", func() {
+		csr := servingCSR([]string{hostname}, nil)
+		csr.Spec.Groups = []string{"system:authenticated"}
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(MatchError(ContainSubstring(nodesGroup)))
+	})
+
+	It("should require the node to exist with the providerID of the machine", func() {
+		csr := servingCSR([]string{hostname}, nil)
+		err := validateServingCSR(csr, hostname, machine, nil)
+		Expect(err).To(MatchError(ContainSubstring("does not exist")))
+		Expect(isDeniable(err)).To(BeFalse())
+
+		node.Spec.ProviderID = "oci://ocid1.instance.oc1.phx.bbbb"
+		err = validateServingCSR(csr, hostname, machine, node)
+		Expect(err).To(MatchError(ContainSubstring("providerID")))
+		Expect(isDeniable(err)).To(BeTrue())
+		Expect(csrValidationReason(err)).To(Equal(csrNodeMismatchReason))
+	})
+
+	It("should deny with the reason of the failed check", func() {
+		err := validateServingCSR(servingCSR([]string{"kubernetes.default.svc"}, nil), hostname, machine, node)
+		Expect(isDeniable(err)).To(BeTrue())
+		Expect(csrValidationReason(err)).To(Equal(csrUnexpectedSubjectAltNamesReason))
+
+		usages = []certificatesv1.KeyUsage{certificatesv1.UsageServerAuth}
+		err = validateServingCSR(servingCSR([]string{hostname}, nil), hostname, machine, node)
+		Expect(csrValidationReason(err)).To(Equal(csrUnexpectedUsagesReason))
+	})
+})
+
+var _ = Describe("Kubelet client CSR validation", func() {
+	const hostname = "worker-1"
+	var (
+		usages  []certificatesv1.KeyUsage
+		subject pkix.Name
+	)
+
+	BeforeEach(func() {
+		usages = []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageKeyEncipherment, certificatesv1.UsageClientAuth}
+		subject = pkix.Name{CommonName: nodeUserPrefix + hostname, Organization: []string{nodesGroup}}
+	})
+
+	clientCSR := func(username string, dnsNames []string) *certificatesv1.CertificateSigningRequest {
+		groups := []string{"system:serviceaccounts", nodeBootstrapperGroup, "system:authenticated"}
+		return newTestCSR(kubeletClientSignerName, username, groups, usages, subject, dnsNames, nil)
+	}
+
+	It("should accept a bootstrap CSR for a node that has not joined yet", func() {
+		Expect(validateClientCSR(clientCSR(nodeBootstrapperUsername, nil), hostname, nil)).To(Succeed())
+	})
+
+	It("should reject requesters other than the node-bootstrapper", func() {
+		csr := clientCSR("system:serviceaccount:default:builder", nil)
+		Expect(validateClientCSR(csr, hostname, nil)).To(MatchError(ContainSubstring("node-bootstrapper")))
+	})
+
+	It("should reject CSRs for existing nodes", func() {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: hostname}}
+		Expect(validateClientCSR(clientCSR(nodeBootstrapperUsername, nil), hostname, node)).To(MatchError(ContainSubstring("already exists")))
+	})
+
+	It("should reject other organizations, usages and subject alternative names", func() {
+		subject.Organization = []string{"system:masters"}
+		Expect(validateClientCSR(clientCSR(nodeBootstrapperUsername, nil), hostname, nil)).To(MatchError(ContainSubstring("organization")))
+
+		subject.Organization = []string{nodesGroup}
+		Expect(validateClientCSR(clientCSR(nodeBootstrapperUsername, []string{hostname}), hostname, nil)).To(MatchError(ContainSubstring("alternative names")))
+
+		usages = append(usages, certificatesv1.UsageServerAuth)
+		Expect(validateClientCSR(clientCSR(nodeBootstrapperUsername, nil), hostname, nil)).To(MatchError(ContainSubstring("server auth")))
+	})
+})
+
+var _ = Describe("CSR approval limits", func() {
+	var (
+		machine *infrastructurev1beta2.OCIMachine
+		limits  csrApprovalLimits
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		machine = &infrastructurev1beta2.OCIMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-abcde", Namespace: capiSystemNamespace},
+		}
+		limits = approvalLimits(nil)
+		now = time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)
+	})
+
+	pendingCSRs := func(count int, hostname string) []certificatesv1.CertificateSigningRequest {
+		csrs := make([]certificatesv1.CertificateSigningRequest, count)
+		for i := range csrs {
+			csrs[i].Spec.SignerName = kubeletServingSignerName
+			csrs[i].Spec.Username = nodeUserPrefix + hostname
+		}
+		return csrs
+	}
+
+	approvedCSR := func(at time.Time) certificatesv1.CertificateSigningRequest {
+		csr := certificatesv1.CertificateSigningRequest{}
+		csr.Spec.SignerName = kubeletServingSignerName
+		csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{
+			Type:           certificatesv1.CertificateApproved,
+			Reason:         csrApprovedConditionReason,
+			Message:        approvalMessage(machine),
+			LastUpdateTime: metav1.NewTime(at),
+		}}
+		return csr
+	}
+
+	It("should allow pending CSRs up to the allowance plus the machines without nodes", func() {
+		limits.maxPending = 2
+		machines := []infrastructurev1beta2.OCIMachine{*machine, {}, {Spec: infrastructurev1beta2.OCIMachineSpec{ProviderID: swag.String("oci://joined")}}}
+		nodes := []corev1.Node{{Spec: corev1.NodeSpec{ProviderID: "oci://joined"}}}
+
+		reason, _ := checkApprovalLimits(pendingCSRs(4, machine.Name), machines, nodes, machine, limits, now)
+		Expect(reason).To(BeEmpty())
+
+		reason, message := checkApprovalLimits(pendingCSRs(5, machine.Name), machines, nodes, machine, limits, now)
+		Expect(reason).To(Equal(pendingLimitExceededReason))
+		Expect(message).To(ContainSubstring("5 kubelet CSRs are pending"))
+	})
+
+	It("should only count the pending CSRs of the machines of the cluster", func() {
+		limits.maxPending = 0
+		csrs := append(pendingCSRs(1, machine.Name), pendingCSRs(5, "other-cluster-node")...)
+
+		reason, _ := checkApprovalLimits(csrs, []infrastructurev1beta2.OCIMachine{*machine}, nil, machine, limits, now)
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("should only count the approvals of the operator for the machine", func() {
+		limits.maxApprovalsPerMachine = 1
+		foreign := approvedCSR(now)
+		foreign.Status.Conditions[0].Reason = "AutoApproved"
+		other := approvedCSR(now)
+		other.Status.Conditions[0].Message = approvalMessage(&infrastructurev1beta2.OCIMachine{ObjectMeta: metav1.ObjectMeta{Name: "workers-fghij", Namespace: capiSystemNamespace}})
+
+		reason, _ := checkApprovalLimits([]certificatesv1.CertificateSigningRequest{foreign, other}, nil, nil, machine, limits, now)
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("should apply the throttled condition with its own field manager when it changes", func() {
+		var applied []appliedPatch
+		c := newFakeClientBuilder().WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+		r := &CertificateApprovalReconciler{Client: c}
+
+		autoscaler := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "default"}}
+		autoscaler.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Deployed"}}
+		condition := metav1.Condition{Type: csrApprovalThrottledCondition, Status: metav1.ConditionTrue, Reason: pendingLimitExceededReason, Message: "throttled"}
+		Expect(r.applyThrottledCondition(ctx, autoscaler, condition)).To(Succeed())
+
+		Expect(applied).To(HaveLen(1))
+		Expect(applied[0].subResource).To(Equal("status"))
+		Expect(applied[0].options.FieldManager).To(Equal(csrApprovalFieldManager))
+		conditions, _, _ := unstructured.NestedSlice(applied[0].obj.(*unstructured.Unstructured).Object, "status", "conditions")
+		Expect(conditions).To(HaveLen(1))
+		Expect(conditions[0]).To(HaveKeyWithValue("type", csrApprovalThrottledCondition))
+		Expect(conditions[0]).To(HaveKey("lastTransitionTime"))
+
+		autoscaler.Status.Conditions = append(autoscaler.Status.Conditions, condition)
+		Expect(r.applyThrottledCondition(ctx, autoscaler, condition)).To(Succeed())
+		Expect(applied).To(HaveLen(1))
+	})
+
+	It("should limit the approvals per machine within the window", func() {
+		limits.maxApprovalsPerMachine = 2
+		csrs := []certificatesv1.CertificateSigningRequest{
+			approvedCSR(now.Add(-2 * time.Hour)),
+			approvedCSR(now.Add(-10 * time.Minute)),
+		}
+
+		reason, _ := checkApprovalLimits(csrs, nil, nil, machine, limits, now)
+		Expect(reason).To(BeEmpty())
+
+		csrs = append(csrs, approvedCSR(now.Add(-time.Minute)))
+		reason, _ = checkApprovalLimits(csrs, nil, nil, machine, limits, now)
+		Expect(reason).To(Equal(machineLimitExceededReason))
+	})
+
+	It("should apply the limits configured on the OCIClusterAutoscaler", func() {
+		autoscaler := &capiv1alpha1.OCIClusterAutoscaler{}
+		autoscaler.Spec.CSRApproval = capiv1alpha1.CSRApprovalConfig{
+			MaxPendingCSRs:         swag.Int32(10),
+			MaxApprovalsPerMachine: swag.Int32(3),
+			ApprovalWindow:         &metav1.Duration{Duration: 30 * time.Minute},
+		}
+		Expect(approvalLimits(autoscaler)).To(Equal(csrApprovalLimits{maxPending: 10, maxApprovalsPerMachine: 3, window: 30 * time.Minute}))
+	})
+})
+
+var _ = Describe("Pending CSR rechecks", func() {
+	now := time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)
+
+	csrCreated := func(age time.Duration) *certificatesv1.CertificateSigningRequest {
+		return &certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))},
+		}
+	}
+
+	It("should recheck new CSRs often and old CSRs less often", func() {
+		Expect(pendingCSRRecheck(csrCreated(time.Second), now)).To(Equal(minPendingCSRRecheck))
+		Expect(pendingCSRRecheck(csrCreated(20*time.Minute), now)).To(Equal(2 * time.Minute))
+		Expect(pendingCSRRecheck(csrCreated(5*time.Hour), now)).To(Equal(maxPendingCSRRecheck))
+	})
+
+	It("should stop rechecking CSRs about to be garbage collected", func() {
+		Expect(pendingCSRRecheck(csrCreated(pendingCSRExpiry), now)).To(BeZero())
+	})
+
+	It("should match CAPI Machines by name, infrastructure machine and addresses", func() {
+		machine := &capiv1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-7d9f-abcde"},
+			Spec: capiv1beta1.MachineSpec{
+				InfrastructureRef: corev1.ObjectReference{Name: "workers-abcde"},
+				ProviderID:        swag.String("oci://ocid1.instance.oc1.phx.aaaa"),
+			},
+			Status: capiv1beta1.MachineStatus{
+				Addresses: capiv1beta1.MachineAddresses{{Type: capiv1beta1.MachineInternalIP, Address: "10.0.1.15"}},
+			},
+		}
+		Expect(capiMachineIdentities(machine)).To(ConsistOf("workers-7d9f-abcde", "workers-abcde", "ocid1.instance.oc1.phx.aaaa", "10.0.1.15"))
+	})
+
+	It("should index pending kubelet CSRs by node name and serving SANs", func() {
+		serving := newTestCSR(kubeletServingSignerName, "system:node:Workers-ABCDE", nil, nil,
+			pkix.Name{CommonName: "system:node:workers-abcde"}, []string{"worker-1.subnet.vcn.oraclevcn.com"}, []net.IP{net.ParseIP("10.0.1.15")})
+		Expect(indexPendingCSRIdentity(serving)).To(ConsistOf("workers-abcde", "worker-1.subnet.vcn.oraclevcn.com", "10.0.1.15"))
+
+		client := newTestCSR(kubeletClientSignerName, nodeBootstrapperUsername, nil, nil,
+			pkix.Name{CommonName: "system:node:workers-abcde"}, nil, nil)
+		Expect(indexPendingCSRIdentity(client)).To(ConsistOf("workers-abcde"))
+
+		approved := serving.DeepCopy()
+		approved.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}}
+		Expect(indexPendingCSRIdentity(approved)).To(BeEmpty())
+
+		other := newTestCSR("example.com/signer", "system:node:workers-abcde", nil, nil,
+			pkix.Name{CommonName: "system:node:workers-abcde"}, nil, nil)
+		Expect(indexPendingCSRIdentity(other)).To(BeEmpty())
+	})
+
+	It("should only enqueue the pending CSRs requested under an identity of the machine", func() {
+		csr := func(name, signerName, username, commonName string) *certificatesv1.CertificateSigningRequest {
+			req := newTestCSR(signerName, username, nil, nil, pkix.Name{CommonName: commonName}, nil, nil)
+			req.Name = name
+			return req
+		}
+		approved := csr("approved", kubeletServingSignerName, "system:node:workers-abcde", "system:node:workers-abcde")
+		approved.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}}
+
+		c := newFakeClientBuilder().
+			WithIndex(&certificatesv1.CertificateSigningRequest{}, pendingCSRIdentityIndex, indexPendingCSRIdentity).
+			WithObjects(
+				csr("serving", kubeletServingSignerName, "system:node:workers-abcde", "system:node:workers-abcde"),
+				csr("client", kubeletClientSignerName, nodeBootstrapperUsername, "system:node:workers-7d9f-abcde"),
+				csr("other-node", kubeletServingSignerName, "system:node:workers-fghij", "system:node:workers-fghij"),
+				approved,
+			).Build()
+		r := &CertificateApprovalReconciler{Client: c}
+
+		machine := &capiv1beta1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-7d9f-abcde", Namespace: capiSystemNamespace},
+			Spec:       capiv1beta1.MachineSpec{InfrastructureRef: corev1.ObjectReference{Name: "workers-abcde"}},
+		}
+		var names []string
+		for _, request := range r.requestsForMachineCSRs(ctx, machine) {
+			names = append(names, request.Name)
+		}
+		Expect(names).To(ConsistOf("client", "serving"))
+	})
+})
+
+var _ = Describe("Kubelet serving certificate renewal validation", func() {
+	const hostname = "worker-1"
+	var (
+		machine *infrastructurev1beta2.OCIMachine
+		node    *corev1.Node
+		now     time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC)
+		machine = &infrastructurev1beta2.OCIMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers-abcde"},
+			Spec: infrastructurev1beta2.OCIMachineSpec{
+				InstanceId: swag.String("ocid1.instance.oc1.phx.aaaa"),
+				ProviderID: swag.String("oci://ocid1.instance.oc1.phx.aaaa"),
+			},
+			Status: infrastructurev1beta2.OCIMachineStatus{
+				Addresses: []capiv1beta1.MachineAddress{
+					{Type: capiv1beta1.MachineInternalDNS, Address: "worker-1.subnet.vcn.oraclevcn.com"},
+				},
+			},
+		}
+		node = &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: hostname, CreationTimestamp: metav1.NewTime(now.Add(-30 * 24 * time.Hour))},
+			Spec:       corev1.NodeSpec{ProviderID: "oci://ocid1.instance.oc1.phx.aaaa"},
+			Status: corev1.NodeStatus{
+				Addresses: []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "10.0.1.15"}},
+			},
+		}
+	})
+
+	renewalCSR := func(dnsNames []string, ips []net.IP) *certificatesv1.CertificateSigningRequest {
+		usages := []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth}
+		subject := pkix.Name{CommonName: nodeUserPrefix + hostname, Organization: []string{nodesGroup}}
+		csr := newTestCSR(kubeletServingSignerName, nodeUserPrefix+hostname, []string{nodesGroup}, usages, subject, dnsNames, ips)
+		csr.CreationTimestamp = metav1.NewTime(now)
+		return csr
+	}
+
+	It("should only report renewals of nodes a serving certificate was issued to", func() {
+		csr := renewalCSR([]string{hostname}, nil)
+		csr.UID = "renewal"
+		previous := func(name string, created time.Time, issued bool) *certificatesv1.CertificateSigningRequest {
+			p := renewalCSR([]string{hostname}, nil)
+			p.Name = name
+			p.UID = types.UID(name)
+			p.CreationTimestamp = metav1.NewTime(created)
+			p.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}}
+			if issued {
+				p.Status.Certificate = []byte("certificate")
+			}
+			return p
+		}
+		isRenewal := func(objs ...client.Object) bool {
+			r := &CertificateApprovalReconciler{Client: newFakeClientBuilder().WithObjects(objs...).Build()}
+			renewal, err := r.isServingRenewal(ctx, csr, hostname, node)
+			Expect(err).NotTo(HaveOccurred())
+			return renewal
+		}
+
+		Expect(isRenewal(csr)).To(BeFalse())
+		Expect(isRenewal(csr, previous("approved", now.Add(-time.Hour), false))).To(BeFalse())
+		Expect(isRenewal(csr, previous("before-node", node.CreationTimestamp.Add(-time.Hour), true))).To(BeFalse())
+		Expect(isRenewal(csr, previous("issued", now.Add(-time.Hour), true))).To(BeTrue())
+
+		r := &CertificateApprovalReconciler{Client: newFakeClientBuilder().Build()}
+		Expect(r.isServingRenewal(ctx, csr, hostname, nil)).To(BeFalse())
+	})
+
+	It("should only accept the hostname and the addresses of the OCIMachine", func() {
+		csr := renewalCSR([]string{hostname, "worker-1.subnet.vcn.oraclevcn.com"}, nil)
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(Succeed())
+	})
+
+	It("should reject addresses only reported in the node status", func() {
+		csr := renewalCSR([]string{hostname}, []net.IP{net.ParseIP("10.0.1.15")})
+		err := validateServingCSR(csr, hostname, machine, node)
+		Expect(err).To(MatchError(ContainSubstring("10.0.1.15")))
+		Expect(csrValidationReason(err)).To(Equal(csrUnexpectedSubjectAltNamesReason))
+
+		csr = renewalCSR([]string{hostname, "api.example.com"}, nil)
+		Expect(validateServingCSR(csr, hostname, machine, node)).To(MatchError(ContainSubstring("api.example.com")))
+	})
+
+	It("should reject renewals of nodes backed by another instance", func() {
+		node.Spec.ProviderID = "oci://ocid1.instance.oc1.phx.bbbb"
+		csr := renewalCSR([]string{hostname}, nil)
+		err := validateServingCSR(csr, hostname, machine, node)
+		Expect(err).To(MatchError(ContainSubstring("providerID")))
+		Expect(csrValidationReason(err)).To(Equal(csrNodeMismatchReason))
+	})
+})
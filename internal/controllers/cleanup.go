@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+// cleanupRetryDelay is the delay before checking again that the managed objects are gone
+const cleanupRetryDelay = 10 * time.Second
+
+// clusterObjectLists returns empty lists of the CAPI objects of the cluster. They are deleted first, so that
+// CAPI and CAPOCI are still running with their identity and credentials while they release the instances.
+func clusterObjectLists() []client.ObjectList {
+	return []client.ObjectList{
+		&capiv1beta1.ClusterList{},
+		&capiv1beta1.MachineDeploymentList{},
+		&infrastructurev1beta2.OCIClusterList{},
+	}
+}
+
+// managedObjectLists returns empty lists of every other kind of object the operator creates for an
+// OCIClusterAutoscaler, namespaced or cluster-scoped
+func managedObjectLists() []client.ObjectList {
+	lists := []client.ObjectList{
+		&appsv1.DeploymentList{},
+		&corev1.ServiceAccountList{},
+		&corev1.ConfigMapList{},
+		&corev1.ServiceList{},
+		&corev1.SecretList{},
+		&rbacv1.ClusterRoleList{},
+		&rbacv1.ClusterRoleBindingList{},
+		&rbacv1.RoleList{},
+		&rbacv1.RoleBindingList{},
+		&networkingv1.NetworkPolicyList{},
+		&securityv1.SecurityContextConstraintsList{},
+		&infrastructurev1beta2.OCIMachineTemplateList{},
+		&infrastructurev1beta2.OCIClusterIdentityList{},
+	}
+	for _, gvk := range []schema.GroupVersionKind{serviceMonitorGVK, prometheusRuleGVK} {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		lists = append(lists, list)
+	}
+	return lists
+}
+
+// isClusterObject returns true for the objects created for the cluster of the autoscaler alone. The other
+// objects, such as the controllers, the default credentials, the SCC, the RBAC and the NetworkPolicies, have
+// fixed names and are shared by every OCIClusterAutoscaler, whichever applied them last being their owner.
+func (r *OCIClusterAutoscalerReconciler) isClusterObject(obj client.Object, autoscaler *capiv1alpha1.OCIClusterAutoscaler) bool {
+	switch obj.(type) {
+	case *capiv1beta1.Cluster, *capiv1beta1.MachineDeployment, *infrastructurev1beta2.OCICluster,
+		*infrastructurev1beta2.OCIMachineTemplate, *infrastructurev1beta2.OCIClusterIdentity:
+		return true
+	case *corev1.Secret:
+		return client.ObjectKeyFromObject(obj) == r.principalSecretKey(autoscaler)
+	}
+	return false
+}
+
+// cleanup deletes the objects created for the OCIClusterAutoscaler in every namespace, which are found by
+// their owner labels. The CAPI objects of the cluster are deleted first and the other objects only once
+// they are gone. The shared objects are left to the other OCIClusterAutoscalers, which are reconciled once
+// this one is gone to take them over, and are only deleted with the last one. It returns true while some of the
+// objects still exist, for instance because they have finalizers of their own. Kinds whose API is not
+// installed are skipped.
+func (r *OCIClusterAutoscalerReconciler) cleanup(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) (bool, error) {
+	// Foreground deletion keeps the MachineDeployments until their machines are gone
+	remaining, err := r.deleteOwnedObjects(ctx, autoscaler, clusterObjectLists(), metav1.DeletePropagationForeground, nil)
+	if err != nil || remaining {
+		return remaining, err
+	}
+
+	shared, err := r.hasOtherAutoscalers(ctx, autoscaler)
+	if err != nil {
+		return false, err
+	}
+	keep := func(obj client.Object) bool {
+		return shared && !r.isClusterObject(obj, autoscaler)
+	}
+	return r.deleteOwnedObjects(ctx, autoscaler, managedObjectLists(), metav1.DeletePropagationBackground, keep)
+}
+
+// hasOtherAutoscalers returns true when another OCIClusterAutoscaler exists and is not being deleted
+func (r *OCIClusterAutoscalerReconciler) hasOtherAutoscalers(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) (bool, error) {
+	autoscalers := &capiv1alpha1.OCIClusterAutoscalerList{}
+	if err := r.List(ctx, autoscalers); err != nil {
+		return false, fmt.Errorf("failed to list OCIClusterAutoscalers: %w", err)
+	}
+	for _, other := range autoscalers.Items {
+		if other.UID != autoscaler.UID && other.DeletionTimestamp.IsZero() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// deleteOwnedObjects deletes the objects of the lists owned by the OCIClusterAutoscaler, except those to
+// keep. It returns true while some of the objects to delete still exist.
+func (r *OCIClusterAutoscalerReconciler) deleteOwnedObjects(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, lists []client.ObjectList, propagation metav1.DeletionPropagation, keep func(client.Object) bool) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	remaining := false
+	for _, list := range lists {
+		if err := r.List(ctx, list, ownerLabelSelector(autoscaler)); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+			return false, fmt.Errorf("failed to list managed objects: %w", err)
+		}
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			return false, err
+		}
+
+		for _, o := range objects {
+			obj, ok := o.(client.Object)
+			if !ok || !isOwnedBy(obj, autoscaler) || (keep != nil && keep(obj)) {
+				continue
+			}
+			remaining = true
+			if !obj.GetDeletionTimestamp().IsZero() {
+				continue
+			}
+			gvk, err := r.GroupVersionKindFor(obj)
+			if err != nil {
+				return false, err
+			}
+			if err := r.Delete(ctx, obj, client.PropagationPolicy(propagation)); client.IgnoreNotFound(err) != nil {
+				return false, fmt.Errorf("failed to delete %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+			}
+			logger.Info("Deleted managed object", "kind", gvk.Kind, "object", client.ObjectKeyFromObject(obj))
+		}
+	}
+	return remaining, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+)
+
+var _ = Describe("Cleanup", func() {
+	var (
+		autoscaler *capiv1alpha1.OCIClusterAutoscaler
+		cluster    *capiv1beta1.Cluster
+		identity   *infrastructurev1beta2.OCIClusterIdentity
+		principal  *corev1.Secret
+		manager    *appsv1.Deployment
+	)
+
+	owned := func(obj client.Object) client.Object {
+		setOwnerMetadata(obj, autoscaler)
+		return obj
+	}
+
+	BeforeEach(func() {
+		autoscaler = &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "workers", Namespace: "default", UID: "workers-uid"}}
+		cluster = &capiv1beta1.Cluster{ObjectMeta: metav1.ObjectMeta{
+			Name:       clusterName(autoscaler),
+			Namespace:  capiNamespace(autoscaler),
+			Finalizers: []string{capiv1beta1.ClusterFinalizer},
+		}}
+		identity = &infrastructurev1beta2.OCIClusterIdentity{ObjectMeta: metav1.ObjectMeta{Name: clusterName(autoscaler), Namespace: capiNamespace(autoscaler)}}
+		principal = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: principalSecretName(autoscaler), Namespace: operatorNamespace}}
+		manager = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "capi-controller-manager", Namespace: capiSystemNamespace}}
+	})
+
+	exists := func(c client.Client, obj client.Object) bool {
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+		return true
+	}
+
+	releaseCluster := func(c client.Client) {
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+		cluster.Finalizers = nil
+		Expect(c.Update(ctx, cluster)).To(Succeed())
+	}
+
+	It("should only delete the controllers, identity and credentials once the cluster is gone", func() {
+		c := newFakeClientBuilder().WithObjects(autoscaler, owned(cluster), owned(identity), owned(principal), owned(manager)).Build()
+		r := &OCIClusterAutoscalerReconciler{Client: c, OperatorNamespace: operatorNamespace}
+
+		remaining, err := r.cleanup(ctx, autoscaler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeTrue())
+		Expect(exists(c, cluster)).To(BeTrue())
+		Expect(cluster.DeletionTimestamp).NotTo(BeNil())
+		Expect(exists(c, identity)).To(BeTrue())
+		Expect(exists(c, principal)).To(BeTrue())
+		Expect(exists(c, manager)).To(BeTrue())
+
+		// CAPI releases the cluster once its instances are terminated
+		releaseCluster(c)
+
+		remaining, err = r.cleanup(ctx, autoscaler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeTrue())
+		Expect(exists(c, identity)).To(BeFalse())
+		Expect(exists(c, principal)).To(BeFalse())
+		Expect(exists(c, manager)).To(BeFalse())
+
+		remaining, err = r.cleanup(ctx, autoscaler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeFalse())
+	})
+
+	It("should leave the shared objects to the other OCIClusterAutoscalers", func() {
+		other := &capiv1alpha1.OCIClusterAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "other-uid"},
+		}
+		deleting := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{
+			Name:              "deleting",
+			Namespace:         "default",
+			UID:               "deleting-uid",
+			DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			Finalizers:        []string{"ociclusterautoscaler.capi.openshift.io/finalizer"},
+		}}
+		cluster.Finalizers = nil
+		c := newFakeClientBuilder().WithObjects(autoscaler, other, deleting, owned(cluster), owned(identity), owned(principal), owned(manager)).Build()
+		r := &OCIClusterAutoscalerReconciler{Client: c, OperatorNamespace: operatorNamespace}
+
+		Expect(r.cleanup(ctx, autoscaler)).To(BeTrue())
+		Expect(exists(c, cluster)).To(BeFalse())
+
+		remaining, err := r.cleanup(ctx, autoscaler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeTrue())
+		Expect(exists(c, identity)).To(BeFalse())
+		Expect(exists(c, principal)).To(BeFalse())
+		Expect(exists(c, manager)).To(BeTrue())
+
+		remaining, err = r.cleanup(ctx, autoscaler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeFalse())
+		Expect(exists(c, manager)).To(BeTrue())
+
+		// The shared objects go with the last OCIClusterAutoscaler
+		Expect(c.Delete(ctx, other)).To(Succeed())
+		Expect(r.cleanup(ctx, autoscaler)).To(BeTrue())
+		Expect(exists(c, manager)).To(BeFalse())
+	})
+})
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// defaultConfigFileSecretKey is the key of the OCI config file in the secret when the reference names none
+	defaultConfigFileSecretKey = "config"
+
+	// defaultConfigFileProfile is the profile of the OCI config file used when the reference names none
+	defaultConfigFileProfile = "DEFAULT"
+)
+
+// parseOCIConfigFile returns the entries of the profile of an OCI CLI config file. As with the OCI CLI,
+// entries of the DEFAULT profile are inherited by the other profiles.
+func parseOCIConfigFile(content []byte, profile string) (map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			if profiles[name] == nil {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+		default:
+			key, value, found := strings.Cut(text, "=")
+			if !found {
+				return nil, fmt.Errorf("line %d: expected key=value", line)
+			}
+			if current == nil {
+				return nil, fmt.Errorf("line %d: entry outside of a profile", line)
+			}
+			current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, exists := profiles[profile]
+	if !exists {
+		return nil, fmt.Errorf("profile %s not found", profile)
+	}
+	merged := map[string]string{}
+	for key, value := range profiles[defaultConfigFileProfile] {
+		merged[key] = value
+	}
+	for key, value := range entries {
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// ociConfigFileData returns the credentials of the referenced OCI config file profile in the format read
+// by CAPOCI. The private key is read from the key of the Secret named after its key_file.
+func (r *OCIClusterAutoscalerReconciler) ociConfigFileData(ctx context.Context, namespace string, ref *capiv1alpha1.OCIConfigFileRef) (map[string][]byte, error) {
+	content, err := r.secretValue(ctx, namespace, capiv1alpha1.SecretRef{Name: ref.Name, Key: ref.Key}, defaultConfigFileSecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OCI config file: %w", err)
+	}
+	profile := ref.Profile
+	if profile == "" {
+		profile = defaultConfigFileProfile
+	}
+	entries, err := parseOCIConfigFile(content, profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI config file in secret %s: %w", ref.Name, err)
+	}
+
+	data := map[string][]byte{}
+	for key, credentialsKey := range map[string]string{
+		"tenancy":     "tenancy",
+		"region":      "region",
+		"user":        "user",
+		"fingerprint": "fingerprint",
+		"pass_phrase": "passphrase",
+	} {
+		if value := entries[key]; value != "" {
+			data[credentialsKey] = []byte(value)
+		}
+	}
+	if keyFile := entries["key_file"]; keyFile != "" {
+		key, err := r.secretValue(ctx, namespace, capiv1alpha1.SecretRef{Name: ref.Name, Key: path.Base(keyFile)}, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key_file of profile %s: %w", profile, err)
+		}
+		data["key"] = key
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("OCI config file", func() {
+	config := []byte(`# OCI CLI configuration
+[DEFAULT]
+user=ocid1.user.oc1..default
+fingerprint=aa:bb:cc:dd:ee:ff:00:11:22:33:44:55:66:77:88:99
+key_file=~/.oci/oci_api_key.pem
+tenancy=ocid1.tenancy.oc1..example
+region=us-sanjose-1
+
+[PRODUCTION]
+user = ocid1.user.oc1..production
+region = us-phoenix-1
+`)
+
+	It("should read the DEFAULT profile", func() {
+		entries, err := parseOCIConfigFile(config, defaultConfigFileProfile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveKeyWithValue("user", "ocid1.user.oc1..default"))
+		Expect(entries).To(HaveKeyWithValue("key_file", "~/.oci/oci_api_key.pem"))
+		Expect(entries).To(HaveKeyWithValue("region", "us-sanjose-1"))
+	})
+
+	It("should inherit the DEFAULT entries in other profiles", func() {
+		entries, err := parseOCIConfigFile(config, "PRODUCTION")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveKeyWithValue("user", "ocid1.user.oc1..production"))
+		Expect(entries).To(HaveKeyWithValue("region", "us-phoenix-1"))
+		Expect(entries).To(HaveKeyWithValue("tenancy", "ocid1.tenancy.oc1..example"))
+	})
+
+	It("should reject unknown profiles and malformed files", func() {
+		_, err := parseOCIConfigFile(config, "STAGING")
+		Expect(err).To(MatchError(ContainSubstring("profile STAGING not found")))
+
+		_, err = parseOCIConfigFile([]byte("user=ocid1.user.oc1..default\n"), defaultConfigFileProfile)
+		Expect(err).To(HaveOccurred())
+
+		_, err = parseOCIConfigFile([]byte("[DEFAULT]\nuser\n"), defaultConfigFileProfile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not require the OCI fields that the config file provides", func() {
+		spec := &capiv1alpha1.OCIClusterAutoscalerSpec{}
+		spec.Autoscaling.MaxNodes = 1
+		spec.Autoscaling.Shape = "VM.Standard.E4.Flex"
+		spec.OCI.CredentialsConfigRef = &capiv1alpha1.OCIConfigFileRef{Name: "oci-cli-config"}
+		Expect(validateAutoscalerSpec(spec)).To(Succeed())
+
+		spec.OCI.CredentialsConfigRef = nil
+		Expect(validateAutoscalerSpec(spec)).To(MatchError(ContainSubstring("tenancyId and region are required")))
+	})
+})
@@ -4,6 +4,14 @@ const (
 	OCICAPIClusterName  = "oci-capi-cluster"
 	capiSystemNamespace = "capi-system"
 
+	// capiDeploymentName is the CAPI controller manager installed by clusterctl
+	capiDeploymentName = "capi-controller-manager"
+
+	// capociNamespace and capociDeploymentName locate the CAPOCI controller manager installed by clusterctl
+	capociNamespace      = "cluster-api-provider-oci-system"
+	capociDeploymentName = "capoci-controller-manager"
+
 	// Default Images
 	ClusterAutoscalerImage = "registry.k8s.io/autoscaling/cluster-autoscaler:v1.29.0"
+	KubeRBACProxyImage     = "quay.io/brancz/kube-rbac-proxy:v0.19.1"
 )
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/md5"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// credentialsValidCondition reports whether the CAPOCI credentials of the cluster are consistent
+	credentialsValidCondition = "CredentialsValid"
+
+	credentialsValidReason    = "CredentialsValid"
+	invalidOCIDReason         = "InvalidOCID"
+	unknownRegionReason       = "UnknownRegion"
+	realmMismatchReason       = "RealmMismatch"
+	passphraseRequiredReason  = "PassphraseRequired"
+	invalidPrivateKeyReason   = "InvalidPrivateKey"
+	fingerprintMismatchReason = "FingerprintMismatch"
+)
+
+// ocidPattern matches ocid1.<resource type>.<realm>.[region][.future use].<unique ID>
+var ocidPattern = regexp.MustCompile(`^ocid1\.([a-z0-9]+)\.([a-z0-9]+)\.([a-z0-9-]*)(?:\.[a-z0-9-]*)?\.([a-z0-9]+)$`)
+
+// parseOCID returns the resource type and realm of the OCID
+func parseOCID(ocid string) (string, string, error) {
+	match := ocidPattern.FindStringSubmatch(ocid)
+	if match == nil {
+		return "", "", fmt.Errorf("%q is not an OCID", ocid)
+	}
+	return match[1], match[2], nil
+}
+
+// apiKeyFingerprint returns the OCI fingerprint of the public key of the private key: the colon separated
+// MD5 digest of its DER encoding
+func apiKeyFingerprint(privateKey, passphrase []byte) (string, error) {
+	key, err := common.PrivateKeyFromBytesWithPassword(privateKey, passphrase)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	digest := md5.Sum(der)
+	parts := make([]string, len(digest))
+	for i, b := range digest {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// validateCredentials checks the CAPOCI credentials without calling OCI: the format of the tenancy and user
+// OCIDs, that the region is known and in the realm of the OCIDs, and that the private key loads the way
+// CAPOCI loads it and matches the fingerprint. It returns the reason and message of the first failed
+// check, or an empty reason when the credentials are consistent.
+func validateCredentials(data map[string][]byte) (string, string) {
+	region := strings.ToLower(string(data["region"]))
+	regionRealm, err := common.Region(region).RealmID()
+	if err != nil {
+		return unknownRegionReason, fmt.Sprintf("Region %q is not a known OCI region", region)
+	}
+
+	// The credentials keys of the OCIDs are named after their resource type
+	resourceTypes := []string{"tenancy"}
+	if _, ok := data["user"]; ok {
+		resourceTypes = append(resourceTypes, "user")
+	}
+	for _, expected := range resourceTypes {
+		resourceType, realm, err := parseOCID(string(data[expected]))
+		if err != nil {
+			return invalidOCIDReason, fmt.Sprintf("The %s OCID is invalid: %v", expected, err)
+		}
+		if resourceType != expected {
+			return invalidOCIDReason, fmt.Sprintf("The %s OCID %s is the OCID of a %s", expected, data[expected], resourceType)
+		}
+		if realm != regionRealm {
+			return realmMismatchReason, fmt.Sprintf("The %s OCID is in realm %s but region %s is in realm %s", expected, realm, region, regionRealm)
+		}
+	}
+
+	privateKey, ok := data["key"]
+	if !ok {
+		return "", ""
+	}
+	passphrase := data["passphrase"]
+	if block, _ := pem.Decode(privateKey); block != nil && x509.IsEncryptedPEMBlock(block) && len(passphrase) == 0 { //nolint:staticcheck // CAPOCI only supports legacy PEM encryption
+		return passphraseRequiredReason, "The private key is encrypted but no passphraseSecretRef is set"
+	}
+	if len(passphrase) == 0 {
+		passphrase = nil
+	}
+	fingerprint, err := apiKeyFingerprint(privateKey, passphrase)
+	if err != nil {
+		return invalidPrivateKeyReason, fmt.Sprintf("The private key cannot be loaded: %v", err)
+	}
+	if expected := strings.ToLower(strings.TrimSpace(string(data["fingerprint"]))); expected != fingerprint {
+		return fingerprintMismatchReason, fmt.Sprintf("The fingerprint of the private key is %s, not %s", fingerprint, expected)
+	}
+	return "", ""
+}
+
+// reportCredentials sets the CredentialsValid condition of the autoscaler from the CAPOCI credentials and
+// returns an error when they are not consistent
+func reportCredentials(autoscaler *capiv1alpha1.OCIClusterAutoscaler, data map[string][]byte) error {
+	condition := metav1.Condition{
+		Type:    credentialsValidCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  credentialsValidReason,
+		Message: fmt.Sprintf("The %s credentials are consistent", ociAuthType(autoscaler)),
+	}
+	reason, message := validateCredentials(data)
+	if reason != "" {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reason
+		condition.Message = message
+	}
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, condition)
+	if reason != "" {
+		return fmt.Errorf("invalid OCI credentials: %s", message)
+	}
+	return nil
+}
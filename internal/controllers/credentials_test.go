@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("Credential validation", func() {
+	var (
+		plain, encrypted []byte
+		fingerprint      string
+		data             map[string][]byte
+	)
+
+	BeforeEach(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		plain = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		block, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key), []byte("secret"), x509.PEMCipherAES128) //nolint:staticcheck // OCI API keys use legacy PEM encryption
+		Expect(err).NotTo(HaveOccurred())
+		encrypted = pem.EncodeToMemory(block)
+
+		fingerprint, err = apiKeyFingerprint(plain, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		data = map[string][]byte{
+			"tenancy":     []byte("ocid1.tenancy.oc1..aaaaaaaaexample"),
+			"user":        []byte("ocid1.user.oc1..aaaaaaaaexample"),
+			"region":      []byte("us-sanjose-1"),
+			"fingerprint": []byte(fingerprint),
+			"key":         plain,
+		}
+	})
+
+	It("should compute the fingerprint the way OCI does", func() {
+		Expect(fingerprint).To(MatchRegexp(`^([0-9a-f]{2}:){15}[0-9a-f]{2}$`))
+		decrypted, err := apiKeyFingerprint(encrypted, []byte("secret"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decrypted).To(Equal(fingerprint))
+	})
+
+	It("should accept consistent user principal credentials", func() {
+		reason, message := validateCredentials(data)
+		Expect(reason).To(BeEmpty(), message)
+	})
+
+	It("should accept encrypted keys with their passphrase", func() {
+		data["key"] = encrypted
+		data["passphrase"] = []byte("secret")
+		reason, message := validateCredentials(data)
+		Expect(reason).To(BeEmpty(), message)
+	})
+
+	It("should accept instance principal credentials without user or key", func() {
+		reason, message := validateCredentials(map[string][]byte{
+			"tenancy":              data["tenancy"],
+			"region":               data["region"],
+			"useInstancePrincipal": []byte("true"),
+		})
+		Expect(reason).To(BeEmpty(), message)
+	})
+
+	DescribeTable("should report the first inconsistency",
+		func(update func(map[string][]byte), expectedReason string) {
+			update(data)
+			reason, message := validateCredentials(data)
+			Expect(reason).To(Equal(expectedReason))
+			Expect(message).NotTo(BeEmpty())
+		},
+		Entry("unknown region", func(d map[string][]byte) { d["region"] = []byte("us-nowhere-1") }, unknownRegionReason),
+		Entry("malformed tenancy OCID", func(d map[string][]byte) { d["tenancy"] = []byte("tenancy") }, invalidOCIDReason),
+		Entry("user OCID as tenancy", func(d map[string][]byte) { d["tenancy"] = d["user"] }, invalidOCIDReason),
+		Entry("OCID of another realm", func(d map[string][]byte) { d["user"] = []byte("ocid1.user.oc2..aaaaaaaaexample") }, realmMismatchReason),
+		Entry("encrypted key without passphrase", func(d map[string][]byte) { d["key"] = encrypted }, passphraseRequiredReason),
+		Entry("wrong passphrase", func(d map[string][]byte) {
+			d["key"] = encrypted
+			d["passphrase"] = []byte("wrong")
+		}, invalidPrivateKeyReason),
+		Entry("not a private key", func(d map[string][]byte) { d["key"] = []byte("not a key") }, invalidPrivateKeyReason),
+		Entry("fingerprint of another key", func(d map[string][]byte) {
+			d["fingerprint"] = []byte("aa:bb:cc:dd:ee:ff:00:11:22:33:44:55:66:77:88:99")
+		}, fingerprintMismatchReason),
+	)
+
+	It("should report the result in the CredentialsValid condition", func() {
+		autoscaler := &capiv1alpha1.OCIClusterAutoscaler{}
+		Expect(reportCredentials(autoscaler, data)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(autoscaler.Status.Conditions, credentialsValidCondition)).To(BeTrue())
+
+		data["fingerprint"] = []byte("aa:bb:cc:dd:ee:ff:00:11:22:33:44:55:66:77:88:99")
+		Expect(reportCredentials(autoscaler, data)).NotTo(Succeed())
+		condition := meta.FindStatusCondition(autoscaler.Status.Conditions, credentialsValidCondition)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(fingerprintMismatchReason))
+	})
+})
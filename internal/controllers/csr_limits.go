@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	defaultMaxPendingCSRs         = 100
+	defaultMaxApprovalsPerMachine = 6
+	defaultApprovalWindow         = time.Hour
+
+	// csrApprovalThrottledCondition is set on the OCIClusterAutoscaler while CSR approval is paused by the limits
+	csrApprovalThrottledCondition = "CSRApprovalThrottled"
+
+	pendingLimitExceededReason  = "PendingLimitExceeded"
+	machineLimitExceededReason  = "MachineApprovalLimitExceeded"
+	approvalWithinLimitsReason  = "WithinLimits"
+	throttledApprovalRetryDelay = time.Minute
+)
+
+// csrApprovalLimits are the limits applied before approving a CSR
+type csrApprovalLimits struct {
+	maxPending             int
+	maxApprovalsPerMachine int
+	window                 time.Duration
+}
+
+// approvalLimits returns the CSR approval limits configured on the autoscaler, or the defaults
+// when the machine does not belong to any OCIClusterAutoscaler
+func approvalLimits(autoscaler *capiv1alpha1.OCIClusterAutoscaler) csrApprovalLimits {
+	limits := csrApprovalLimits{
+		maxPending:             defaultMaxPendingCSRs,
+		maxApprovalsPerMachine: defaultMaxApprovalsPerMachine,
+		window:                 defaultApprovalWindow,
+	}
+	if autoscaler == nil {
+		return limits
+	}
+
+	config := autoscaler.Spec.CSRApproval
+	if config.MaxPendingCSRs != nil {
+		limits.maxPending = int(*config.MaxPendingCSRs)
+	}
+	if config.MaxApprovalsPerMachine != nil {
+		limits.maxApprovalsPerMachine = int(*config.MaxApprovalsPerMachine)
+	}
+	if config.ApprovalWindow != nil {
+		limits.window = config.ApprovalWindow.Duration
+	}
+	return limits
+}
+
+// checkApprovalLimits returns the reason and message explaining why CSRs for the machine must not be
+// approved at the moment, or empty strings when the limits allow approving them. The pending kubelet CSRs
+// matching the machines of the cluster are bounded by the number of those machines without a node plus the
+// configured allowance, and each machine gets a bounded number of approvals per window.
+func checkApprovalLimits(csrs []certificatesv1.CertificateSigningRequest, machines []infrastructurev1beta2.OCIMachine, nodes []corev1.Node, machine *infrastructurev1beta2.OCIMachine, limits csrApprovalLimits, now time.Time) (string, string) {
+	pending := 0
+	for i := range csrs {
+		csr := &csrs[i]
+		if !isKubeletCSR(csr) || isCSRApproved(csr) || isCSRDenied(csr) {
+			continue
+		}
+		if hostname, err := getCSRHostname(csr); err == nil && len(matchOCIMachines(machines, hostname)) > 0 {
+			pending++
+		}
+	}
+
+	providerIDs := map[string]bool{}
+	for _, node := range nodes {
+		providerIDs[node.Spec.ProviderID] = true
+	}
+	withoutNode := 0
+	for _, m := range machines {
+		if m.Spec.ProviderID == nil || !providerIDs[*m.Spec.ProviderID] {
+			withoutNode++
+		}
+	}
+
+	if maxPending := withoutNode + limits.maxPending; pending > maxPending {
+		return pendingLimitExceededReason, fmt.Sprintf("%d kubelet CSRs are pending for the machines of the cluster, more than the %d allowed for %d OCIMachines without a node", pending, maxPending, withoutNode)
+	}
+
+	key := client.ObjectKeyFromObject(machine)
+	since := now.Add(-limits.window)
+	approved := 0
+	for i := range csrs {
+		if approvedAt, ok := approvalTime(&csrs[i], key); ok && approvedAt.After(since) {
+			approved++
+		}
+	}
+
+	if approved >= limits.maxApprovalsPerMachine {
+		return machineLimitExceededReason, fmt.Sprintf("%d CSRs were approved for OCIMachine %s/%s within %s, the maximum allowed", approved, machine.Namespace, machine.Name, limits.window)
+	}
+
+	return "", ""
+}
+
+// approvalTime returns when the operator approved the CSR for the OCIMachine, or false when the CSR was not
+// approved by the operator for it
+func approvalTime(csr *certificatesv1.CertificateSigningRequest, machine types.NamespacedName) (time.Time, bool) {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type != certificatesv1.CertificateApproved || condition.Reason != csrApprovedConditionReason {
+			continue
+		}
+		match := decisionMachinePattern.FindStringSubmatch(condition.Message)
+		if match == nil || (types.NamespacedName{Namespace: match[1], Name: match[2]}) != machine {
+			continue
+		}
+		return condition.LastUpdateTime.Time, true
+	}
+	return time.Time{}, false
+}
+
+// throttleApproval checks the approval limits of the OCIClusterAutoscaler the machine belongs to, if any, and
+// reports the outcome in its status and in the CSR approval metrics. It returns true when the CSR
+// must not be approved for now.
+func (r *CertificateApprovalReconciler) throttleApproval(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, machine *infrastructurev1beta2.OCIMachine) (bool, error) {
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := r.List(ctx, csrs); err != nil {
+		return false, fmt.Errorf("failed to list CSRs: %w", err)
+	}
+	machines := &infrastructurev1beta2.OCIMachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(machine.Namespace), client.MatchingLabels{capiv1beta1.ClusterNameLabel: machineScope(machine).cluster}); err != nil {
+		return false, fmt.Errorf("failed to list OCIMachines: %w", err)
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return false, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	reason, message := checkApprovalLimits(csrs.Items, machines.Items, nodes.Items, machine, approvalLimits(autoscaler), time.Now())
+
+	cluster := machine.Labels[capiv1beta1.ClusterNameLabel]
+	if reason != "" {
+		csrApprovalThrottled.WithLabelValues(cluster).Set(1)
+		csrApprovalsThrottledTotal.WithLabelValues(cluster, reason).Inc()
+	} else {
+		csrApprovalThrottled.WithLabelValues(cluster).Set(0)
+	}
+
+	if autoscaler != nil {
+		condition := metav1.Condition{
+			Type:    csrApprovalThrottledCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  approvalWithinLimitsReason,
+			Message: "Kubelet CSRs are approved within the configured limits",
+		}
+		if reason != "" {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = reason
+			condition.Message = message
+		}
+		if err := r.applyThrottledCondition(ctx, autoscaler, condition); err != nil {
+			return false, err
+		}
+	}
+
+	return reason != "", nil
+}
+
+// applyThrottledCondition applies the CSRApprovalThrottled condition to the status of the OCIClusterAutoscaler
+// when it changed. The condition is applied with its own field manager so that it is kept alongside the
+// conditions written by the OCIClusterAutoscaler controller.
+func (r *CertificateApprovalReconciler) applyThrottledCondition(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, condition metav1.Condition) error {
+	conditions := append([]metav1.Condition(nil), autoscaler.Status.Conditions...)
+	if !meta.SetStatusCondition(&conditions, condition) {
+		return nil
+	}
+
+	applied, err := runtime.DefaultUnstructuredConverter.ToUnstructured(meta.FindStatusCondition(conditions, condition.Type))
+	if err != nil {
+		return err
+	}
+	status := partialObject(capiv1alpha1.GroupVersion.WithKind("OCIClusterAutoscaler"), autoscaler.Namespace, autoscaler.Name)
+	if err := unstructured.SetNestedSlice(status.Object, []interface{}{applied}, "status", "conditions"); err != nil {
+		return err
+	}
+	if err := r.Status().Patch(ctx, status, client.Apply, client.FieldOwner(csrApprovalFieldManager), client.ForceOwnership); err != nil {
+		return fmt.Errorf("failed to update %s condition: %w", csrApprovalThrottledCondition, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Values of the request label of the CSR approval metrics
+	csrRequestInitial = "initial"
+	csrRequestRenewal = "renewal"
+)
+
+// isServingRenewal returns true when a serving certificate was already issued to the node: an earlier
+// kubelet-serving CSR of the node, created since the node registered, was approved and signed.
+// kube-controller-manager deletes issued CSRs after an hour, so a renewal whose previous CSR is gone is
+// validated as a first request, which is held to the same subject alternative names.
+func (r *CertificateApprovalReconciler) isServingRenewal(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, hostname string, node *corev1.Node) (bool, error) {
+	if node == nil {
+		return false, nil
+	}
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := r.List(ctx, csrs); err != nil {
+		return false, fmt.Errorf("failed to list CSRs: %w", err)
+	}
+	for i := range csrs.Items {
+		if isIssuedServingCertificate(&csrs.Items[i], csr, hostname, node) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isIssuedServingCertificate returns true if previous is a kubelet-serving CSR of the node, created since the
+// node registered and before csr, that was approved and signed
+func isIssuedServingCertificate(previous, csr *certificatesv1.CertificateSigningRequest, hostname string, node *corev1.Node) bool {
+	if previous.UID == csr.UID || previous.Spec.SignerName != kubeletServingSignerName || previous.Spec.Username != nodeUserPrefix+hostname {
+		return false
+	}
+	if previous.CreationTimestamp.Before(&node.CreationTimestamp) || !previous.CreationTimestamp.Before(&csr.CreationTimestamp) {
+		return false
+	}
+	return isCSRApproved(previous) && len(previous.Status.Certificate) > 0
+}
+
+// validateNodeInstance checks that the node is backed by the instance of the OCIMachine
+func validateNodeInstance(node *corev1.Node, machine *infrastructurev1beta2.OCIMachine) error {
+	if machine.Spec.ProviderID == nil || *machine.Spec.ProviderID == "" {
+		return pendingCSR("OCIMachine %s has no providerID yet", machine.Name)
+	}
+	if node.Spec.ProviderID != *machine.Spec.ProviderID {
+		return invalidCSR(csrNodeMismatchReason, "node %s has providerID %q instead of %q", node.Name, node.Spec.ProviderID, *machine.Spec.ProviderID)
+	}
+	if machine.Spec.InstanceId != nil && *machine.Spec.InstanceId != "" && !strings.HasSuffix(node.Spec.ProviderID, *machine.Spec.InstanceId) {
+		return invalidCSR(csrNodeMismatchReason, "node %s has providerID %q which does not name instance %s", node.Name, node.Spec.ProviderID, *machine.Spec.InstanceId)
+	}
+	return nil
+}
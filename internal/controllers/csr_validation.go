@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	kubeletClientSignerName  = "kubernetes.io/kube-apiserver-client-kubelet"
+	kubeletServingSignerName = "kubernetes.io/kubelet-serving"
+
+	nodeUserPrefix = "system:node:"
+	nodesGroup     = "system:nodes"
+
+	// nodeBootstrapperUsername and nodeBootstrapperGroup identify the ServiceAccount new nodes
+	// request their first client certificate with
+	nodeBootstrapperUsername = "system:serviceaccount:openshift-machine-config-operator:node-bootstrapper"
+	nodeBootstrapperGroup    = "system:serviceaccounts:openshift-machine-config-operator"
+)
+
+// Reasons CSRs failing validation are denied with
+const (
+	csrUnexpectedSignerReason          = "UnexpectedSigner"
+	csrUnexpectedRequesterReason       = "UnexpectedRequester"
+	csrUnexpectedUsagesReason          = "UnexpectedUsages"
+	csrUnexpectedSubjectReason         = "UnexpectedSubject"
+	csrUnexpectedSubjectAltNamesReason = "UnexpectedSubjectAltNames"
+	csrMalformedRequestReason          = "MalformedRequest"
+	csrNodeMismatchReason              = "NodeMismatch"
+)
+
+// csrValidationError explains why a CSR failed validation
+type csrValidationError struct {
+	// reason is the reason the CSR is denied with
+	reason  string
+	message string
+	// pending is set when the CSR may become valid once the node or machine has progressed, in
+	// which case it is never denied
+	pending bool
+}
+
+func (e *csrValidationError) Error() string {
+	return e.message
+}
+
+// invalidCSR returns a validation error for a CSR that must be denied with the given reason
+func invalidCSR(reason, format string, args ...interface{}) error {
+	return &csrValidationError{reason: reason, message: fmt.Sprintf(format, args...)}
+}
+
+// pendingCSR returns a validation error for a CSR that cannot be approved yet
+func pendingCSR(format string, args ...interface{}) error {
+	return &csrValidationError{message: fmt.Sprintf(format, args...), pending: true}
+}
+
+// isDeniable returns true if the validation error allows denying the CSR
+func isDeniable(err error) bool {
+	var invalid *csrValidationError
+	return errors.As(err, &invalid) && !invalid.pending
+}
+
+// csrValidationReason returns the reason a CSR failing validation with err is denied with
+func csrValidationReason(err error) string {
+	var invalid *csrValidationError
+	if errors.As(err, &invalid) && invalid.reason != "" {
+		return invalid.reason
+	}
+	return csrMalformedRequestReason
+}
+
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// csrValidation is the outcome of the validation of a CSR
+type csrValidation struct {
+	// renewal is set when the CSR renews the certificate of an existing node
+	renewal bool
+	// invalid explains why the CSR must not be approved
+	invalid error
+}
+
+// validateCSR checks the request against the OCIMachine it was matched to. The returned error reports
+// a failure to run the checks, the outcome of the checks is reported in the csrValidation.
+func (r *CertificateApprovalReconciler) validateCSR(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, hostname string, machine *infrastructurev1beta2.OCIMachine) (csrValidation, error) {
+	node := &corev1.Node{}
+	err := r.Get(ctx, types.NamespacedName{Name: hostname}, node)
+	if apierrors.IsNotFound(err) {
+		node = nil
+	} else if err != nil {
+		return csrValidation{}, fmt.Errorf("failed to get node %s: %w", hostname, err)
+	}
+
+	switch csr.Spec.SignerName {
+	case kubeletClientSignerName:
+		return csrValidation{invalid: validateClientCSR(csr, hostname, node)}, nil
+	case kubeletServingSignerName:
+		// Renewals are held to the names of the OCIMachine like the first request. The addresses in the
+		// node status are reported by the kubelet itself and do not widen them.
+		renewal, err := r.isServingRenewal(ctx, csr, hostname, node)
+		if err != nil {
+			return csrValidation{}, err
+		}
+		return csrValidation{renewal: renewal, invalid: validateServingCSR(csr, hostname, machine, node)}, nil
+	default:
+		return csrValidation{invalid: invalidCSR(csrUnexpectedSignerReason, "unexpected signer %s", csr.Spec.SignerName)}, nil
+	}
+}
+
+// validateClientCSR checks that a kube-apiserver-client-kubelet CSR is a bootstrap request for a node
+// that has not joined yet: it must come from the node-bootstrapper ServiceAccount, only ask for the
+// usages of a kubelet client certificate and carry no subject alternative names. Refusing names of
+// existing nodes keeps a leaked bootstrap token from being used to impersonate them.
+func validateClientCSR(csr *certificatesv1.CertificateSigningRequest, hostname string, node *corev1.Node) error {
+	if csr.Spec.Username != nodeBootstrapperUsername {
+		return invalidCSR(csrUnexpectedRequesterReason, "requester %q is not %s", csr.Spec.Username, nodeBootstrapperUsername)
+	}
+	if !slices.Contains(csr.Spec.Groups, nodeBootstrapperGroup) {
+		return invalidCSR(csrUnexpectedRequesterReason, "requester %q is not in group %s", csr.Spec.Username, nodeBootstrapperGroup)
+	}
+
+	if err := validateUsages(csr.Spec.Usages,
+		[]certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+		[]certificatesv1.KeyUsage{certificatesv1.UsageKeyEncipherment}); err != nil {
+		return err
+	}
+
+	certReq, err := parseCSR(csr)
+	if err != nil {
+		return invalidCSR(csrMalformedRequestReason, "%v", err)
+	}
+	if certReq.Subject.CommonName != nodeUserPrefix+hostname {
+		return invalidCSR(csrUnexpectedSubjectReason, "subject common name %q does not name node %s", certReq.Subject.CommonName, hostname)
+	}
+	if !slices.Equal(certReq.Subject.Organization, []string{nodesGroup}) {
+		return invalidCSR(csrUnexpectedSubjectReason, "subject organization %v is not [%s]", certReq.Subject.Organization, nodesGroup)
+	}
+	if len(certReq.DNSNames) > 0 || len(certReq.IPAddresses) > 0 || len(certReq.EmailAddresses) > 0 || len(certReq.URIs) > 0 {
+		return invalidCSR(csrUnexpectedSubjectAltNamesReason, "subject alternative names are not allowed")
+	}
+
+	if node != nil {
+		return invalidCSR(csrNodeMismatchReason, "node %s already exists", hostname)
+	}
+
+	return nil
+}
+
+// validateServingCSR checks that a kubelet-serving CSR is requested by the node itself, only asks for
+// the usages of a serving certificate and only names the node and the addresses of its instance
+func validateServingCSR(csr *certificatesv1.CertificateSigningRequest, hostname string, machine *infrastructurev1beta2.OCIMachine, node *corev1.Node) error {
+	certReq, err := validateServingRequest(csr, hostname)
+	if err != nil {
+		return err
+	}
+
+	dnsNames, ips := machineAddresses(machine)
+	dnsNames = append(dnsNames, hostname)
+	for _, name := range certReq.DNSNames {
+		if !slices.ContainsFunc(dnsNames, func(allowed string) bool { return strings.EqualFold(allowed, name) }) {
+			return invalidCSR(csrUnexpectedSubjectAltNamesReason, "DNS name %q is not a hostname of OCIMachine %s", name, machine.Name)
+		}
+	}
+	for _, ip := range certReq.IPAddresses {
+		if !slices.ContainsFunc(ips, ip.Equal) {
+			return invalidCSR(csrUnexpectedSubjectAltNamesReason, "IP address %s is not an address of OCIMachine %s", ip, machine.Name)
+		}
+	}
+
+	if node == nil {
+		return pendingCSR("node %s does not exist yet", hostname)
+	}
+	return validateNodeInstance(node, machine)
+}
+
+// validateServingRequest checks the requester, usages and subject of a kubelet-serving CSR and returns
+// the parsed certificate request
+func validateServingRequest(csr *certificatesv1.CertificateSigningRequest, hostname string) (*x509.CertificateRequest, error) {
+	if csr.Spec.Username != nodeUserPrefix+hostname {
+		return nil, invalidCSR(csrUnexpectedRequesterReason, "requester %q is not node %s", csr.Spec.Username, hostname)
+	}
+	if !slices.Contains(csr.Spec.Groups, nodesGroup) {
+		return nil, invalidCSR(csrUnexpectedRequesterReason, "requester %q is not in group %s", csr.Spec.Username, nodesGroup)
+	}
+
+	if err := validateUsages(csr.Spec.Usages,
+		[]certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+		[]certificatesv1.KeyUsage{certificatesv1.UsageKeyEncipherment}); err != nil {
+		return nil, err
+	}
+
+	certReq, err := parseCSR(csr)
+	if err != nil {
+		return nil, invalidCSR(csrMalformedRequestReason, "%v", err)
+	}
+	if certReq.Subject.CommonName != nodeUserPrefix+hostname {
+		return nil, invalidCSR(csrUnexpectedSubjectReason, "subject common name %q does not name node %s", certReq.Subject.CommonName, hostname)
+	}
+	if !slices.Equal(certReq.Subject.Organization, []string{nodesGroup}) {
+		return nil, invalidCSR(csrUnexpectedSubjectReason, "subject organization %v is not [%s]", certReq.Subject.Organization, nodesGroup)
+	}
+	if len(certReq.EmailAddresses) > 0 || len(certReq.URIs) > 0 {
+		return nil, invalidCSR(csrUnexpectedSubjectAltNamesReason, "email and URI subject alternative names are not allowed")
+	}
+	if len(certReq.DNSNames) == 0 && len(certReq.IPAddresses) == 0 {
+		return nil, invalidCSR(csrUnexpectedSubjectAltNamesReason, "no DNS or IP subject alternative names requested")
+	}
+	return certReq, nil
+}
+
+// validateUsages checks that usages contains every required usage and nothing besides the optional ones
+func validateUsages(usages, required, optional []certificatesv1.KeyUsage) error {
+	for _, usage := range required {
+		if !slices.Contains(usages, usage) {
+			return invalidCSR(csrUnexpectedUsagesReason, "usage %q is missing", usage)
+		}
+	}
+	for _, usage := range usages {
+		if !slices.Contains(required, usage) && !slices.Contains(optional, usage) {
+			return invalidCSR(csrUnexpectedUsagesReason, "usage %q is not allowed", usage)
+		}
+	}
+	return nil
+}
+
+// machineAddresses returns the hostnames and IPs reported for the instance of the OCIMachine
+func machineAddresses(machine *infrastructurev1beta2.OCIMachine) ([]string, []net.IP) {
+	var dnsNames []string
+	var ips []net.IP
+	for _, address := range machine.Status.Addresses {
+		switch address.Type {
+		case capiv1beta1.MachineHostName, capiv1beta1.MachineInternalDNS, capiv1beta1.MachineExternalDNS:
+			dnsNames = append(dnsNames, address.Address)
+		case capiv1beta1.MachineInternalIP, capiv1beta1.MachineExternalIP:
+			if ip := net.ParseIP(address.Address); ip != nil {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return dnsNames, ips
+}
+
+// parseCSR decodes the PEM encoded certificate request of the CSR
+func parseCSR(csr *certificatesv1.CertificateSigningRequest) (*x509.CertificateRequest, error) {
+	if len(csr.Spec.Request) == 0 {
+		return nil, fmt.Errorf("CSR request is empty")
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("failed to decode PEM block or invalid block type")
+	}
+
+	certReq, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate request: %w", err)
+	}
+	return certReq, nil
+}
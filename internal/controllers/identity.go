@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// defaultPrivateKeySecretKey is the key of the private key in the secret when the reference names none
+	defaultPrivateKeySecretKey = "private_key"
+
+	// defaultPassphraseSecretKey is the key of the passphrase in the secret when the reference names none
+	defaultPassphraseSecretKey = "passphrase"
+
+	// ociClusterIdentityKind is the kind referenced by the identityRef of the OCICluster
+	ociClusterIdentityKind = "OCIClusterIdentity"
+)
+
+// ociAuthType returns the kind of principal CAPOCI authenticates as for the cluster
+func ociAuthType(instance *capiv1alpha1.OCIClusterAutoscaler) capiv1alpha1.OCIAuthType {
+	if instance.Spec.OCI.Auth == nil {
+		return capiv1alpha1.OCIAuthUserPrincipal
+	}
+	return instance.Spec.OCI.Auth.Type
+}
+
+// userPrincipal returns the user API key configured for the cluster, falling back to the deprecated
+// fields of the OCI configuration when no authentication is configured. It returns nil when the cluster
+// does not authenticate as a user.
+func userPrincipal(instance *capiv1alpha1.OCIClusterAutoscaler) *capiv1alpha1.UserPrincipalAuth {
+	if auth := instance.Spec.OCI.Auth; auth != nil {
+		return auth.UserPrincipal
+	}
+	return &capiv1alpha1.UserPrincipalAuth{
+		UserID:              instance.Spec.OCI.UserID,
+		Fingerprint:         instance.Spec.OCI.Fingerprint,
+		PrivateKeySecretRef: instance.Spec.OCI.PrivateKeySecretRef,
+	}
+}
+
+// principalType returns the CAPOCI principal type of the authentication type
+func principalType(authType capiv1alpha1.OCIAuthType) infrastructurev1beta2.PrincipalType {
+	switch authType {
+	case capiv1alpha1.OCIAuthInstancePrincipal:
+		return infrastructurev1beta2.InstancePrincipal
+	case capiv1alpha1.OCIAuthWorkloadIdentity:
+		return infrastructurev1beta2.WorkloadPrincipal
+	default:
+		return infrastructurev1beta2.UserPrincipal
+	}
+}
+
+// validateOCIAuth checks that the configured principal is complete. Fields of the user API key may be
+// left to the OCI config file when the deprecated fields are used.
+func validateOCIAuth(oci *capiv1alpha1.OCIConfig) error {
+	if oci.Auth == nil {
+		if oci.CredentialsConfigRef == nil && (oci.UserID == "" || oci.Fingerprint == "" || oci.PrivateKeySecretRef.Name == "") {
+			return fmt.Errorf("userId, fingerprint and privateKeySecretRef are required when neither auth nor credentialsConfigRef is set")
+		}
+		return nil
+	}
+
+	switch oci.Auth.Type {
+	case capiv1alpha1.OCIAuthUserPrincipal:
+		user := oci.Auth.UserPrincipal
+		if user == nil {
+			return fmt.Errorf("auth.userPrincipal is required when auth.type is %s", capiv1alpha1.OCIAuthUserPrincipal)
+		}
+		if user.UserID == "" || user.Fingerprint == "" || user.PrivateKeySecretRef.Name == "" {
+			return fmt.Errorf("auth.userPrincipal: userId, fingerprint and privateKeySecretRef are required")
+		}
+	case capiv1alpha1.OCIAuthInstancePrincipal, capiv1alpha1.OCIAuthWorkloadIdentity:
+		if oci.Auth.UserPrincipal != nil {
+			return fmt.Errorf("auth.userPrincipal must not be set when auth.type is %s", oci.Auth.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported auth.type %q", oci.Auth.Type)
+	}
+	return nil
+}
+
+// ociCredentialsData returns the credentials of the principal of the cluster in the format read by CAPOCI.
+// Fields of the OCI configuration take precedence over the OCI config file profile. The private key is
+// only read for user principals.
+func (r *OCIClusterAutoscalerReconciler) ociCredentialsData(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	if ref := autoscaler.Spec.OCI.CredentialsConfigRef; ref != nil {
+		fileData, err := r.ociConfigFileData(ctx, autoscaler.Namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		data = fileData
+	}
+	setCredential(data, "tenancy", autoscaler.Spec.OCI.TenancyID)
+	setCredential(data, "region", autoscaler.Spec.OCI.Region)
+	if err := requireCredentials(data, "tenancy", "region"); err != nil {
+		return nil, err
+	}
+
+	user := userPrincipal(autoscaler)
+	if user == nil {
+		data = map[string][]byte{"tenancy": data["tenancy"], "region": data["region"]}
+		if ociAuthType(autoscaler) == capiv1alpha1.OCIAuthInstancePrincipal {
+			data["useInstancePrincipal"] = []byte("true")
+		}
+		return data, nil
+	}
+
+	setCredential(data, "user", user.UserID)
+	setCredential(data, "fingerprint", user.Fingerprint)
+	if user.PrivateKeySecretRef.Name != "" {
+		privateKey, err := r.secretValue(ctx, autoscaler.Namespace, user.PrivateKeySecretRef, defaultPrivateKeySecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		data["key"] = privateKey
+	}
+	if user.PassphraseSecretRef != nil {
+		passphrase, err := r.secretValue(ctx, autoscaler.Namespace, *user.PassphraseSecretRef, defaultPassphraseSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key passphrase: %w", err)
+		}
+		data["passphrase"] = passphrase
+	}
+
+	if err := requireCredentials(data, "user", "fingerprint", "key"); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// requireCredentials returns an error when one of the credentials is set neither in the OCI configuration
+// nor in the OCI config file
+func requireCredentials(data map[string][]byte, keys ...string) error {
+	for _, key := range keys {
+		if len(data[key]) == 0 {
+			return fmt.Errorf("OCI credentials are incomplete: %s is set neither in spec.oci nor in the OCI config file", key)
+		}
+	}
+	return nil
+}
+
+// setCredential sets the credential unless the value is empty
+func setCredential(data map[string][]byte, key, value string) {
+	if value != "" {
+		data[key] = []byte(value)
+	}
+}
+
+// secretValue returns the value of the referenced key of a Secret of the namespace. The Secret is read
+// from the API server since the cache only holds the Secrets managed by the operator.
+func (r *OCIClusterAutoscalerReconciler) secretValue(ctx context.Context, namespace string, ref capiv1alpha1.SecretRef, defaultKey string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", ref.Name, err)
+	}
+
+	key := ref.Key
+	if key == "" {
+		key = defaultKey
+	}
+	value, exists := secret.Data[key]
+	if !exists {
+		return nil, fmt.Errorf("key %s not found in secret %s", key, ref.Name)
+	}
+	return value, nil
+}
+
+// principalSecretName returns the name of the Secret holding the user principal credentials of the cluster.
+// The Secrets of the clusters of every CAPI namespace are kept in the namespace of the operator, so the name
+// is made unique with the suffix of the cluster.
+func principalSecretName(instance *capiv1alpha1.OCIClusterAutoscaler) string {
+	return clusterName(instance) + "-" + clusterCredentialsSuffix(instance) + "-oci-user-principal"
+}
+
+// principalSecretKey returns the Secret holding the user principal credentials of the cluster
+func (r *OCIClusterAutoscalerReconciler) principalSecretKey(instance *capiv1alpha1.OCIClusterAutoscaler) types.NamespacedName {
+	return types.NamespacedName{Name: principalSecretName(instance), Namespace: r.OperatorNamespace}
+}
+
+// clusterIdentityRef returns the reference to the OCIClusterIdentity of the cluster
+func clusterIdentityRef(instance *capiv1alpha1.OCIClusterAutoscaler) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: infrastructurev1beta2.GroupVersion.String(),
+		Kind:       ociClusterIdentityKind,
+		Name:       clusterName(instance),
+		Namespace:  capiNamespace(instance),
+	}
+}
+
+// reconcileClusterIdentity creates the OCIClusterIdentity of the cluster, which references the Secret holding
+// the API key of user principals. The identity can only be used from the CAPI namespace.
+func (r *OCIClusterAutoscalerReconciler) reconcileClusterIdentity(ctx context.Context, instance *capiv1alpha1.OCIClusterAutoscaler) error {
+	namespace := capiNamespace(instance)
+	key := r.principalSecretKey(instance)
+	principalSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+		},
+	}
+
+	identity := &infrastructurev1beta2.OCIClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName(instance),
+			Namespace: namespace,
+		},
+	}
+	setOwnerMetadata(identity, instance)
+	identity.Spec = infrastructurev1beta2.OCIClusterIdentitySpec{
+		Type: principalType(ociAuthType(instance)),
+		AllowedNamespaces: &infrastructurev1beta2.AllowedNamespaces{
+			NamespaceList: []string{namespace},
+		},
+	}
+
+	if userPrincipal(instance) == nil {
+		if err := r.Delete(ctx, principalSecret); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete user principal Secret: %w", err)
+		}
+	} else {
+		// The Secret is written by reconcileOCICredentials, which restarts CAPOCI when it changes
+		identity.Spec.PrincipalSecret = corev1.SecretReference{Name: key.Name, Namespace: key.Namespace}
+	}
+
+	if err := r.apply(ctx, identity); err != nil {
+		return fmt.Errorf("failed to create/update OCIClusterIdentity: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+)
+
+// operatorNamespace is the namespace the operator runs in during the tests
+const operatorNamespace = "oci-capi-operator-system"
+
+// newReconciledAutoscaler returns an autoscaler authenticating as an instance principal, with the finalizer
+// already set so that Reconcile renders the managed objects
+func newReconciledAutoscaler() *capiv1alpha1.OCIClusterAutoscaler {
+	autoscaler := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{
+		Name:       "workers",
+		Namespace:  "default",
+		Finalizers: []string{"ociclusterautoscaler.capi.openshift.io/finalizer"},
+	}}
+	autoscaler.Spec.OCI.TenancyID = "ocid1.tenancy.oc1..example"
+	autoscaler.Spec.OCI.Region = "us-ashburn-1"
+	autoscaler.Spec.OCI.CompartmentID = "ocid1.compartment.oc1..example"
+	autoscaler.Spec.OCI.Auth = &capiv1alpha1.OCIAuthConfig{Type: capiv1alpha1.OCIAuthInstancePrincipal}
+	autoscaler.Spec.Autoscaling.MaxNodes = 3
+	autoscaler.Spec.Autoscaling.Shape = "VM.Standard.E4.Flex"
+	return autoscaler
+}
+
+// reconcileAutoscaler runs Reconcile on the autoscaler with the CAPI and CAPOCI CRDs and the objects installed,
+// and returns the applied patches and the client
+func reconcileAutoscaler(autoscaler *capiv1alpha1.OCIClusterAutoscaler, objs ...client.Object) ([]appliedPatch, client.Client) {
+	var applied []appliedPatch
+	crd := func(name string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	c := newFakeClientBuilder().
+		WithObjects(autoscaler, crd("clusters.cluster.x-k8s.io"), crd("ociclusters.infrastructure.cluster.x-k8s.io")).
+		WithObjects(objs...).
+		WithStatusSubresource(autoscaler).
+		WithInterceptorFuncs(recordApplyPatches(&applied)).
+		Build()
+	r := &OCIClusterAutoscalerReconciler{Client: c, Scheme: c.Scheme(), APIReader: c, OperatorNamespace: operatorNamespace}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(autoscaler)})
+	Expect(err).NotTo(HaveOccurred())
+	return applied, c
+}
+
+// appliedObject returns the applied patch of the object of the kind, name and namespace
+func appliedObject(applied []appliedPatch, kind, namespace, name string) client.Object {
+	for _, patch := range applied {
+		obj := patch.obj
+		if obj.GetObjectKind().GroupVersionKind().Kind == kind && obj.GetNamespace() == namespace && obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+var _ = Describe("OCI authentication", func() {
+	user := &capiv1alpha1.UserPrincipalAuth{
+		UserID:              "ocid1.user.oc1..example",
+		Fingerprint:         "aa:bb:cc:dd:ee:ff:00:11:22:33:44:55:66:77:88:99",
+		PrivateKeySecretRef: capiv1alpha1.SecretRef{Name: "oci-private-key"},
+	}
+
+	It("should fall back to the deprecated user key fields when auth is not set", func() {
+		instance := &capiv1alpha1.OCIClusterAutoscaler{}
+		instance.Spec.OCI.UserID = user.UserID
+		instance.Spec.OCI.Fingerprint = user.Fingerprint
+		instance.Spec.OCI.PrivateKeySecretRef = user.PrivateKeySecretRef
+
+		Expect(ociAuthType(instance)).To(Equal(capiv1alpha1.OCIAuthUserPrincipal))
+		Expect(userPrincipal(instance)).To(Equal(user))
+		Expect(validateOCIAuth(&instance.Spec.OCI)).To(Succeed())
+
+		instance.Spec.OCI.Fingerprint = ""
+		Expect(validateOCIAuth(&instance.Spec.OCI)).NotTo(Succeed())
+	})
+
+	It("should use the configured user principal", func() {
+		instance := &capiv1alpha1.OCIClusterAutoscaler{}
+		instance.Spec.OCI.Auth = &capiv1alpha1.OCIAuthConfig{Type: capiv1alpha1.OCIAuthUserPrincipal, UserPrincipal: user}
+
+		Expect(userPrincipal(instance)).To(Equal(user))
+		Expect(principalType(ociAuthType(instance))).To(Equal(infrastructurev1beta2.UserPrincipal))
+		Expect(validateOCIAuth(&instance.Spec.OCI)).To(Succeed())
+
+		instance.Spec.OCI.Auth.UserPrincipal = nil
+		Expect(validateOCIAuth(&instance.Spec.OCI)).NotTo(Succeed())
+	})
+
+	It("should not use a user key for instance principals and workload identities", func() {
+		for authType, principal := range map[capiv1alpha1.OCIAuthType]infrastructurev1beta2.PrincipalType{
+			capiv1alpha1.OCIAuthInstancePrincipal: infrastructurev1beta2.InstancePrincipal,
+			capiv1alpha1.OCIAuthWorkloadIdentity:  infrastructurev1beta2.WorkloadPrincipal,
+		} {
+			instance := &capiv1alpha1.OCIClusterAutoscaler{}
+			instance.Spec.OCI.Auth = &capiv1alpha1.OCIAuthConfig{Type: authType}
+
+			Expect(userPrincipal(instance)).To(BeNil(), string(authType))
+			Expect(principalType(ociAuthType(instance))).To(Equal(principal), string(authType))
+			Expect(validateOCIAuth(&instance.Spec.OCI)).To(Succeed(), string(authType))
+
+			instance.Spec.OCI.Auth.UserPrincipal = user
+			Expect(validateOCIAuth(&instance.Spec.OCI)).NotTo(Succeed(), string(authType))
+		}
+	})
+
+	It("should reference the OCIClusterIdentity of the cluster from the CAPI namespace", func() {
+		instance := &capiv1alpha1.OCIClusterAutoscaler{}
+		instance.Name = "autoscaler"
+		instance.Spec.CAPI.Namespace = "workload-capi"
+		instance.Spec.CAPI.ClusterName = "workload"
+
+		ref := clusterIdentityRef(instance)
+		Expect(ref.Kind).To(Equal("OCIClusterIdentity"))
+		Expect(ref.APIVersion).To(Equal(infrastructurev1beta2.GroupVersion.String()))
+		Expect(ref.Namespace).To(Equal("workload-capi"))
+		Expect(ref.Name).To(Equal("workload"))
+	})
+
+	It("should apply the OCIClusterIdentity and reference it from the OCICluster when reconciling", func() {
+		applied, _ := reconcileAutoscaler(newReconciledAutoscaler())
+
+		identity, ok := appliedObject(applied, "OCIClusterIdentity", capiSystemNamespace, "workers").(*infrastructurev1beta2.OCIClusterIdentity)
+		Expect(ok).To(BeTrue())
+		Expect(identity.Spec.Type).To(Equal(infrastructurev1beta2.InstancePrincipal))
+		Expect(identity.Spec.AllowedNamespaces.NamespaceList).To(ConsistOf(capiSystemNamespace))
+
+		ociCluster, ok := appliedObject(applied, "OCICluster", capiSystemNamespace, "workers").(*infrastructurev1beta2.OCICluster)
+		Expect(ok).To(BeTrue())
+		Expect(ociCluster.Spec.IdentityRef).NotTo(BeNil())
+		Expect(ociCluster.Spec.IdentityRef.Kind).To(Equal("OCIClusterIdentity"))
+		Expect(ociCluster.Spec.IdentityRef.Name).To(Equal("workers"))
+		Expect(ociCluster.Spec.IdentityRef.Namespace).To(Equal(capiSystemNamespace))
+
+		Expect(appliedObject(applied, "Cluster", capiSystemNamespace, "workers")).NotTo(BeNil())
+	})
+
+	It("should reference the user principal Secret in the namespace of the operator", func() {
+		instance := newReconciledAutoscaler()
+		instance.Spec.OCI.Auth = &capiv1alpha1.OCIAuthConfig{Type: capiv1alpha1.OCIAuthUserPrincipal, UserPrincipal: user}
+		var applied []appliedPatch
+		c := newFakeClientBuilder().WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+		r := &OCIClusterAutoscalerReconciler{Client: c, OperatorNamespace: operatorNamespace}
+
+		Expect(r.reconcileClusterIdentity(ctx, instance)).To(Succeed())
+		identity, ok := appliedObject(applied, "OCIClusterIdentity", capiSystemNamespace, "workers").(*infrastructurev1beta2.OCIClusterIdentity)
+		Expect(ok).To(BeTrue())
+		Expect(identity.Spec.PrincipalSecret.Namespace).To(Equal(operatorNamespace))
+		Expect(identity.Spec.PrincipalSecret.Name).To(HavePrefix("workers-"))
+
+		other := instance.DeepCopy()
+		other.Spec.CAPI.Namespace = "workload-capi"
+		Expect(r.principalSecretKey(other)).NotTo(Equal(r.principalSecretKey(instance)))
+	})
+})
@@ -0,0 +1,323 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	defaultCSRRetention    = 24 * time.Hour
+	defaultNodeGracePeriod = 15 * time.Minute
+
+	// janitorInterval is the delay between two garbage collections for an OCIClusterAutoscaler
+	janitorInterval = 30 * time.Minute
+
+	// taintNodeShutdown is set by the cloud node lifecycle controller on nodes whose instance is shut down
+	taintNodeShutdown = "node.cloudprovider.kubernetes.io/shutdown"
+
+	// Reasons of the events recording the objects deleted by the janitor
+	csrDeletedEventReason  = "CSRDeleted"
+	nodeDeletedEventReason = "NodeDeleted"
+
+	// nodeNotDeletedEventReason is the reason of the events reporting orphaned nodes whose instance is not
+	// known to be terminated
+	nodeNotDeletedEventReason = "NodeNotDeleted"
+)
+
+// decisionMachinePattern extracts the OCIMachine named by the approval and denial messages of the operator
+var decisionMachinePattern = regexp.MustCompile(`for OCIMachine ([^/\s]+)/([^:\s]+)`)
+
+// JanitorReconciler garbage collects the CSRs and Node objects left behind by the machines of the
+// OCIClusterAutoscalers once the autoscaler removed them
+type JanitorReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// APIReader lists the events of the OCIMachines, which the manager cache does not hold
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=delete
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=list
+
+// Reconcile deletes the stale CSRs and orphaned Nodes of the cluster of the OCIClusterAutoscaler
+func (r *JanitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	autoscaler := &capiv1alpha1.OCIClusterAutoscaler{}
+	if err := r.Get(ctx, req.NamespacedName, autoscaler); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !autoscaler.DeletionTimestamp.IsZero() || autoscaler.Spec.Cleanup.Disabled {
+		return ctrl.Result{}, nil
+	}
+
+	namespace := capiNamespace(autoscaler)
+	machines := &infrastructurev1beta2.OCIMachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			// Nothing to clean up before CAPOCI is installed
+			return ctrl.Result{RequeueAfter: janitorInterval}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to list OCIMachines: %w", err)
+	}
+	capiMachines := &capiv1beta1.MachineList{}
+	if err := r.List(ctx, capiMachines, client.InNamespace(namespace)); err != nil && !meta.IsNoMatchError(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to list Machines: %w", err)
+	}
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := r.List(ctx, csrs); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list CSRs: %w", err)
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	now := time.Now()
+	retention, grace := cleanupPeriods(autoscaler)
+	scope := clusterScope{namespace: namespace, cluster: clusterName(autoscaler)}
+
+	var deletedCSRs, deletedNodes, keptNodes []string
+	for _, csr := range staleCSRs(csrs.Items, namespace, machines.Items, retention, now) {
+		if err := r.Delete(ctx, csr, client.Preconditions{UID: &csr.UID}); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete CSR %s: %w", csr.Name, err)
+		}
+		logger.Info("Deleted CSR of deleted machine", "csr", csr.Name)
+		deletedCSRs = append(deletedCSRs, csr.Name)
+	}
+	orphaned := orphanedNodes(nodes.Items, scope, machines.Items, capiMachines.Items, grace, now)
+	var terminated map[string]bool
+	if len(orphaned) > 0 {
+		var err error
+		if terminated, err = r.terminatedMachines(ctx, namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	for _, node := range orphaned {
+		// CAPI names the OCIMachines after their Machine
+		if !terminated[node.Annotations[capiv1beta1.MachineAnnotation]] {
+			logger.Info("Not deleting node of deleted machine, its instance is not known to be terminated", "node", node.Name, "providerID", node.Spec.ProviderID)
+			keptNodes = append(keptNodes, node.Name)
+			continue
+		}
+		if err := r.Delete(ctx, node, client.Preconditions{UID: &node.UID}); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete node %s: %w", node.Name, err)
+		}
+		logger.Info("Deleted node of deleted machine", "node", node.Name, "providerID", node.Spec.ProviderID)
+		deletedNodes = append(deletedNodes, node.Name)
+	}
+
+	if err := r.reportCleanup(ctx, autoscaler, deletedCSRs, deletedNodes, keptNodes, now); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: janitorInterval}, nil
+}
+
+// terminatedMachines returns the names of the OCIMachines of the namespace that CAPOCI recorded as deleted
+// after terminating their instance. CAPOCI only records the InstanceTerminated event once OCI reports the
+// instance as terminated, so it is the evidence that the node of the machine will not come back. Events
+// expire after an hour by default: the nodes of machines deleted earlier are reported but not deleted.
+func (r *JanitorReconciler) terminatedMachines(ctx context.Context, namespace string) (map[string]bool, error) {
+	events := &corev1.EventList{}
+	if err := r.APIReader.List(ctx, events, client.InNamespace(namespace), client.MatchingFields{
+		"involvedObject.kind": "OCIMachine",
+		"reason":              infrastructurev1beta2.InstanceTerminatedReason,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list OCIMachine events: %w", err)
+	}
+
+	terminated := make(map[string]bool, len(events.Items))
+	for i := range events.Items {
+		terminated[events.Items[i].InvolvedObject.Name] = true
+	}
+	return terminated, nil
+}
+
+// reportCleanup records the deleted objects in the events, metrics and status of the OCIClusterAutoscaler,
+// along with the orphaned nodes left in place because their instance is not known to be terminated
+func (r *JanitorReconciler) reportCleanup(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, deletedCSRs, deletedNodes, keptNodes []string, now time.Time) error {
+	if len(deletedCSRs) == 0 && len(deletedNodes) == 0 && len(keptNodes) == 0 {
+		return nil
+	}
+
+	cluster := clusterName(autoscaler)
+	janitorDeletionsTotal.WithLabelValues(cluster, "CertificateSigningRequest").Add(float64(len(deletedCSRs)))
+	janitorDeletionsTotal.WithLabelValues(cluster, "Node").Add(float64(len(deletedNodes)))
+	if r.Recorder != nil {
+		if len(deletedCSRs) > 0 {
+			r.Recorder.Eventf(autoscaler, corev1.EventTypeNormal, csrDeletedEventReason, "Deleted %d CSRs of deleted machines: %s", len(deletedCSRs), strings.Join(deletedCSRs, ", "))
+		}
+		for _, node := range deletedNodes {
+			r.Recorder.Eventf(autoscaler, corev1.EventTypeNormal, nodeDeletedEventReason, "Deleted node %s of a deleted machine", node)
+		}
+		for _, node := range keptNodes {
+			r.Recorder.Eventf(autoscaler, corev1.EventTypeWarning, nodeNotDeletedEventReason, "Node %s of a deleted machine is down but its instance is not known to be terminated, delete it once it is", node)
+		}
+	}
+
+	patch := client.MergeFrom(autoscaler.DeepCopy())
+	autoscaler.Status.Cleanup = &capiv1alpha1.CleanupStatus{
+		LastCleanupTime: metav1.NewTime(now),
+		DeletedCSRs:     deletedCSRs,
+		DeletedNodes:    deletedNodes,
+		OrphanedNodes:   keptNodes,
+	}
+	if err := r.Status().Patch(ctx, autoscaler, patch); err != nil {
+		return fmt.Errorf("failed to update cleanup status: %w", err)
+	}
+	return nil
+}
+
+// cleanupPeriods returns the CSR retention and node grace period configured on the autoscaler, or the defaults
+func cleanupPeriods(autoscaler *capiv1alpha1.OCIClusterAutoscaler) (time.Duration, time.Duration) {
+	retention, grace := defaultCSRRetention, defaultNodeGracePeriod
+	if d := autoscaler.Spec.Cleanup.CSRRetention; d != nil {
+		retention = d.Duration
+	}
+	if d := autoscaler.Spec.Cleanup.NodeGracePeriod; d != nil {
+		grace = d.Duration
+	}
+	return retention, grace
+}
+
+// csrDecision returns the OCIMachine the CSR was approved or denied for by the operator and when the
+// decision was taken. It returns false for pending CSRs and CSRs decided by another approver.
+func csrDecision(csr *certificatesv1.CertificateSigningRequest) (types.NamespacedName, time.Time, bool) {
+	for _, condition := range csr.Status.Conditions {
+		if condition.Type != certificatesv1.CertificateApproved && condition.Type != certificatesv1.CertificateDenied {
+			continue
+		}
+		if !strings.HasPrefix(condition.Reason, csrApprovalReasonPrefix) {
+			continue
+		}
+		match := decisionMachinePattern.FindStringSubmatch(condition.Message)
+		if match == nil {
+			continue
+		}
+		decided := condition.LastUpdateTime.Time
+		if decided.IsZero() {
+			decided = csr.CreationTimestamp.Time
+		}
+		return types.NamespacedName{Namespace: match[1], Name: match[2]}, decided, true
+	}
+	return types.NamespacedName{}, time.Time{}, false
+}
+
+// staleCSRs returns the CSRs approved or denied by the operator for OCIMachines of the namespace that no
+// longer exist, once they were decided longer than the retention period ago
+func staleCSRs(csrs []certificatesv1.CertificateSigningRequest, namespace string, machines []infrastructurev1beta2.OCIMachine, retention time.Duration, now time.Time) []*certificatesv1.CertificateSigningRequest {
+	existing := make(map[string]bool, len(machines))
+	for i := range machines {
+		existing[machines[i].Name] = true
+	}
+
+	var stale []*certificatesv1.CertificateSigningRequest
+	for i := range csrs {
+		csr := &csrs[i]
+		machine, decided, ok := csrDecision(csr)
+		if !ok || machine.Namespace != namespace || existing[machine.Name] {
+			continue
+		}
+		if now.Sub(decided) >= retention {
+			stale = append(stale, csr)
+		}
+	}
+	return stale
+}
+
+// orphanedNodes returns the Nodes CAPI linked to a Machine of the cluster when neither that Machine nor an
+// OCIMachine matching the node exist anymore, and whose kubelet is down. They are only deleted once their
+// OCIMachine is known to be terminated, see terminatedMachines.
+func orphanedNodes(nodes []corev1.Node, scope clusterScope, machines []infrastructurev1beta2.OCIMachine, capiMachines []capiv1beta1.Machine, grace time.Duration, now time.Time) []*corev1.Node {
+	existing := make(map[string]bool, len(capiMachines))
+	for i := range capiMachines {
+		existing[capiMachines[i].Name] = true
+	}
+
+	var orphaned []*corev1.Node
+	for i := range nodes {
+		node := &nodes[i]
+		annotations := node.GetAnnotations()
+		if annotations[capiv1beta1.ClusterNamespaceAnnotation] != scope.namespace || annotations[capiv1beta1.ClusterNameAnnotation] != scope.cluster {
+			continue
+		}
+		machine := annotations[capiv1beta1.MachineAnnotation]
+		if machine == "" || existing[machine] {
+			continue
+		}
+		if len(matchOCIMachines(machines, node.Name)) > 0 {
+			continue
+		}
+		if providerID := strings.TrimPrefix(node.Spec.ProviderID, "oci://"); providerID != "" && len(matchOCIMachines(machines, providerID)) > 0 {
+			continue
+		}
+		if isNodeDown(node, grace, now) {
+			orphaned = append(orphaned, node)
+		}
+	}
+	return orphaned
+}
+
+// isNodeDown returns true when the instance of the node is shut down according to the cloud provider, or
+// when its kubelet stopped reporting for longer than the grace period. Neither signal tells a terminated
+// instance from a stopped or unreachable one.
+func isNodeDown(node *corev1.Node, grace time.Duration, now time.Time) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == taintNodeShutdown {
+			return true
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionUnknown && now.Sub(condition.LastTransitionTime.Time) >= grace
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *JanitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("janitor").
+		For(&capiv1alpha1.OCIClusterAutoscaler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
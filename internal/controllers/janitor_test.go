@@ -0,0 +1,294 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/go-openapi/swag"
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+)
+
+var _ = Describe("Janitor", func() {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	existing := &infrastructurev1beta2.OCIMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers-abcde", Namespace: "capi-system"},
+		Spec: infrastructurev1beta2.OCIMachineSpec{
+			ProviderID: swag.String("oci://ocid1.instance.oc1.phx.aaaa"),
+		},
+	}
+	deleted := &infrastructurev1beta2.OCIMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: "workers-fghij", Namespace: "capi-system"},
+	}
+	machines := []infrastructurev1beta2.OCIMachine{*existing}
+
+	decidedCSR := func(name string, conditionType certificatesv1.RequestConditionType, reason, message string, age time.Duration) certificatesv1.CertificateSigningRequest {
+		return certificatesv1.CertificateSigningRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: certificatesv1.CertificateSigningRequestStatus{
+				Conditions: []certificatesv1.CertificateSigningRequestCondition{
+					{Type: conditionType, Status: corev1.ConditionTrue, Reason: reason, Message: message, LastUpdateTime: metav1.NewTime(now.Add(-age))},
+				},
+			},
+		}
+	}
+
+	Describe("stale CSRs", func() {
+		It("should return CSRs decided for deleted machines after the retention period", func() {
+			csrs := []certificatesv1.CertificateSigningRequest{
+				decidedCSR("approved", certificatesv1.CertificateApproved, csrApprovedConditionReason, approvalMessage(deleted), 25*time.Hour),
+				decidedCSR("denied", certificatesv1.CertificateDenied, csrApprovalReasonPrefix+"SANMismatch", denialMessage(deleted, errors.New("unexpected SAN")), 25*time.Hour),
+				decidedCSR("recent", certificatesv1.CertificateApproved, csrApprovedConditionReason, approvalMessage(deleted), time.Hour),
+				decidedCSR("running", certificatesv1.CertificateApproved, csrApprovedConditionReason, approvalMessage(existing), 25*time.Hour),
+			}
+
+			stale := staleCSRs(csrs, "capi-system", machines, defaultCSRRetention, now)
+			Expect(stale).To(HaveLen(2))
+			Expect(stale[0].Name).To(Equal("approved"))
+			Expect(stale[1].Name).To(Equal("denied"))
+		})
+
+		It("should leave CSRs decided by other approvers or for other namespaces alone", func() {
+			other := deleted.DeepCopy()
+			other.Namespace = "other"
+			csrs := []certificatesv1.CertificateSigningRequest{
+				decidedCSR("kcm", certificatesv1.CertificateApproved, "AutoApproved", "Auto approving kubelet client certificate after SubjectAccessReview.", 25*time.Hour),
+				decidedCSR("other", certificatesv1.CertificateApproved, csrApprovedConditionReason, approvalMessage(other), 25*time.Hour),
+				{ObjectMeta: metav1.ObjectMeta{Name: "pending"}},
+			}
+
+			Expect(staleCSRs(csrs, "capi-system", machines, defaultCSRRetention, now)).To(BeEmpty())
+		})
+	})
+
+	Describe("orphaned nodes", func() {
+		scope := clusterScope{namespace: "capi-system", cluster: "workload"}
+		capiMachines := []capiv1beta1.Machine{{ObjectMeta: metav1.ObjectMeta{Name: "workers-7d9f-abcde", Namespace: "capi-system"}}}
+
+		node := func(name, machine, providerID string, ready corev1.ConditionStatus, since time.Duration) corev1.Node {
+			return corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Annotations: map[string]string{
+						capiv1beta1.ClusterNamespaceAnnotation: "capi-system",
+						capiv1beta1.ClusterNameAnnotation:      "workload",
+						capiv1beta1.MachineAnnotation:          machine,
+					},
+				},
+				Spec: corev1.NodeSpec{ProviderID: providerID},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: ready, LastTransitionTime: metav1.NewTime(now.Add(-since))},
+					},
+				},
+			}
+		}
+
+		It("should return unreachable nodes of deleted machines after the grace period", func() {
+			nodes := []corev1.Node{
+				node("gone", "workers-7d9f-fghij", "oci://ocid1.instance.oc1.phx.bbbb", corev1.ConditionUnknown, time.Hour),
+				node("recent", "workers-7d9f-klmno", "oci://ocid1.instance.oc1.phx.cccc", corev1.ConditionUnknown, time.Minute),
+				node("ready", "workers-7d9f-pqrst", "oci://ocid1.instance.oc1.phx.dddd", corev1.ConditionTrue, time.Hour),
+			}
+
+			orphaned := orphanedNodes(nodes, scope, machines, capiMachines, defaultNodeGracePeriod, now)
+			Expect(orphaned).To(HaveLen(1))
+			Expect(orphaned[0].Name).To(Equal("gone"))
+		})
+
+		It("should return nodes marked as shut down right away", func() {
+			shutdown := node("shutdown", "workers-7d9f-fghij", "oci://ocid1.instance.oc1.phx.bbbb", corev1.ConditionFalse, time.Minute)
+			shutdown.Spec.Taints = []corev1.Taint{{Key: taintNodeShutdown, Effect: corev1.TaintEffectNoSchedule}}
+
+			Expect(orphanedNodes([]corev1.Node{shutdown}, scope, machines, capiMachines, defaultNodeGracePeriod, now)).To(HaveLen(1))
+		})
+
+		It("should keep nodes whose Machine or OCIMachine still exist", func() {
+			nodes := []corev1.Node{
+				node("machine", "workers-7d9f-abcde", "", corev1.ConditionUnknown, time.Hour),
+				node("ocimachine", "workers-7d9f-fghij", "oci://ocid1.instance.oc1.phx.aaaa", corev1.ConditionUnknown, time.Hour),
+			}
+
+			Expect(orphanedNodes(nodes, scope, machines, capiMachines, defaultNodeGracePeriod, now)).To(BeEmpty())
+		})
+
+		It("should keep nodes of other clusters and nodes not created by CAPI", func() {
+			other := node("other", "workers-7d9f-fghij", "", corev1.ConditionUnknown, time.Hour)
+			other.Annotations[capiv1beta1.ClusterNameAnnotation] = "other"
+			unmanaged := node("unmanaged", "", "", corev1.ConditionUnknown, time.Hour)
+			unmanaged.Annotations = nil
+
+			Expect(orphanedNodes([]corev1.Node{other, unmanaged}, scope, machines, capiMachines, defaultNodeGracePeriod, now)).To(BeEmpty())
+		})
+	})
+	Describe("Reconcile", func() {
+		var (
+			autoscaler *capiv1alpha1.OCIClusterAutoscaler
+			recorder   *record.FakeRecorder
+		)
+
+		BeforeEach(func() {
+			autoscaler = &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: "default"}}
+			recorder = record.NewFakeRecorder(10)
+		})
+
+		reconcileJanitor := func(objs ...client.Object) client.Client {
+			c := newFakeClientBuilder().
+				WithObjects(append(objs, autoscaler, existing.DeepCopy())...).
+				WithStatusSubresource(&capiv1alpha1.OCIClusterAutoscaler{}).
+				WithIndex(&corev1.Event{}, "reason", func(obj client.Object) []string {
+					return []string{obj.(*corev1.Event).Reason}
+				}).
+				WithIndex(&corev1.Event{}, "involvedObject.kind", func(obj client.Object) []string {
+					return []string{obj.(*corev1.Event).InvolvedObject.Kind}
+				}).
+				Build()
+			r := &JanitorReconciler{Client: c, Recorder: recorder, APIReader: c}
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(autoscaler)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(janitorInterval))
+			return c
+		}
+
+		staleObjects := func() []client.Object {
+			csr := decidedCSR("stale", certificatesv1.CertificateApproved, csrApprovedConditionReason, approvalMessage(deleted), 0)
+			csr.Status.Conditions[0].LastUpdateTime = metav1.NewTime(time.Now().Add(-25 * time.Hour))
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "gone",
+					Annotations: map[string]string{
+						capiv1beta1.ClusterNamespaceAnnotation: "capi-system",
+						capiv1beta1.ClusterNameAnnotation:      "workload",
+						capiv1beta1.MachineAnnotation:          "workers-7d9f-fghij",
+					},
+				},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+					{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+				}},
+			}
+			return []client.Object{&csr, node}
+		}
+
+		// terminatedEvent is the event CAPOCI records once the instance of the OCIMachine is terminated
+		terminatedEvent := func(machine string) *corev1.Event {
+			return &corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: machine + ".terminated", Namespace: "capi-system"},
+				InvolvedObject: corev1.ObjectReference{Kind: "OCIMachine", Namespace: "capi-system", Name: machine},
+				Reason:         infrastructurev1beta2.InstanceTerminatedReason,
+				Type:           corev1.EventTypeNormal,
+			}
+		}
+
+		It("should delete the stale CSRs and the orphaned nodes of terminated instances and report them", func() {
+			c := reconcileJanitor(append(staleObjects(), terminatedEvent("workers-7d9f-fghij"))...)
+
+			Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "stale"}, &certificatesv1.CertificateSigningRequest{}))).To(BeTrue())
+			Expect(apierrors.IsNotFound(c.Get(ctx, client.ObjectKey{Name: "gone"}, &corev1.Node{}))).To(BeTrue())
+
+			reported := &capiv1alpha1.OCIClusterAutoscaler{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(autoscaler), reported)).To(Succeed())
+			Expect(reported.Status.Cleanup).NotTo(BeNil())
+			Expect(reported.Status.Cleanup.DeletedCSRs).To(ConsistOf("stale"))
+			Expect(reported.Status.Cleanup.DeletedNodes).To(ConsistOf("gone"))
+			Expect(reported.Status.Cleanup.OrphanedNodes).To(BeEmpty())
+			Expect(recorder.Events).To(HaveLen(2))
+		})
+
+		It("should only report the orphaned nodes whose instance is not known to be terminated", func() {
+			other := terminatedEvent("workers-7d9f-klmno")
+			stopped := terminatedEvent("workers-7d9f-fghij")
+			stopped.Name = "workers-7d9f-fghij.stopped"
+			stopped.Reason = "InstanceStopped"
+			c := reconcileJanitor(append(staleObjects(), other, stopped)...)
+
+			Expect(c.Get(ctx, client.ObjectKey{Name: "gone"}, &corev1.Node{})).To(Succeed())
+
+			reported := &capiv1alpha1.OCIClusterAutoscaler{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(autoscaler), reported)).To(Succeed())
+			Expect(reported.Status.Cleanup.DeletedNodes).To(BeEmpty())
+			Expect(reported.Status.Cleanup.OrphanedNodes).To(ConsistOf("gone"))
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ContainElement(And(HavePrefix(corev1.EventTypeWarning), ContainSubstring("Node gone of a deleted machine is down"))))
+		})
+
+		It("should leave everything alone when the cleanup is disabled", func() {
+			autoscaler.Spec.Cleanup.Disabled = true
+			c := newFakeClientBuilder().WithObjects(append(staleObjects(), autoscaler)...).Build()
+			r := &JanitorReconciler{Client: c, Recorder: recorder}
+			Expect(r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(autoscaler)})).To(Equal(ctrl.Result{}))
+
+			Expect(c.Get(ctx, client.ObjectKey{Name: "stale"}, &certificatesv1.CertificateSigningRequest{})).To(Succeed())
+			Expect(c.Get(ctx, client.ObjectKey{Name: "gone"}, &corev1.Node{})).To(Succeed())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should not update the status when nothing was deleted", func() {
+			c := reconcileJanitor()
+
+			reported := &capiv1alpha1.OCIClusterAutoscaler{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(autoscaler), reported)).To(Succeed())
+			Expect(reported.Status.Cleanup).To(BeNil())
+			Expect(recorder.Events).To(BeEmpty())
+		})
+	})
+
+	Describe("cleanup report", func() {
+		It("should record an event per deleted or kept node and one for the deleted CSRs", func() {
+			autoscaler := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: "default"}}
+			c := newFakeClientBuilder().WithObjects(autoscaler).WithStatusSubresource(autoscaler).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &JanitorReconciler{Client: c, Recorder: recorder}
+
+			Expect(r.reportCleanup(ctx, autoscaler, []string{"csr-1", "csr-2"}, []string{"node-1", "node-2"}, []string{"node-3"}, now)).To(Succeed())
+
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			Expect(events).To(ConsistOf(
+				ContainSubstring("Deleted 2 CSRs of deleted machines: csr-1, csr-2"),
+				ContainSubstring("Deleted node node-1"),
+				ContainSubstring("Deleted node node-2"),
+				ContainSubstring("Node node-3 of a deleted machine is down"),
+			))
+
+			reported := &capiv1alpha1.OCIClusterAutoscaler{}
+			Expect(c.Get(ctx, client.ObjectKeyFromObject(autoscaler), reported)).To(Succeed())
+			Expect(reported.Status.Cleanup.LastCleanupTime.Time).To(BeTemporally("==", now))
+			Expect(reported.Status.Cleanup.DeletedNodes).To(Equal([]string{"node-1", "node-2"}))
+			Expect(reported.Status.Cleanup.OrphanedNodes).To(Equal([]string{"node-3"}))
+		})
+	})
+})
@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// Deployment and configuration of the OpenShift cluster-machine-approver
+	machineApproverNamespace  = "openshift-cluster-machine-approver"
+	machineApproverDeployment = "machine-approver"
+	machineApproverConfigMap  = "machine-approver-config"
+	machineApproverConfigKey  = "config.yaml"
+
+	// Flags of a cluster-machine-approver approving the CSRs of the CAPI machines of a namespace
+	machineApproverAPIGroupFlag         = "--apigroup"
+	machineApproverMachineNamespaceFlag = "--machine-namespace"
+
+	// csrApprovalCondition reports which controller approves the kubelet CSRs of the cluster
+	csrApprovalCondition = "CSRApproval"
+
+	approvedByOperatorReason          = "ApprovedByOperator"
+	approvalDisabledReason            = "ApprovalDisabled"
+	delegatedToMachineApproverReason  = "DelegatedToMachineApprover"
+	machineApproverUnavailableReason  = "MachineApproverUnavailable"
+	machineApproverNotCAPIAwareReason = "MachineApproverNotCAPIAware"
+
+	// Approval and denial conditions set by the operator on CSRs carry reasons with this prefix and
+	// messages naming csrApproverIdentity, so that they can be told apart from those of other approvers
+	csrApprovalReasonPrefix    = "OCICAPIOperator"
+	csrApprovedConditionReason = csrApprovalReasonPrefix + "Approved"
+	csrApproverIdentity        = "oci-capi-operator/csr-approver"
+)
+
+// machineApproverState describes the cluster-machine-approver found in the cluster
+type machineApproverState struct {
+	// installed is set when the machine-approver Deployment exists
+	installed bool
+	// available is set when the machine-approver Deployment has available replicas
+	available bool
+	// nodeClientCertDisabled is set when the machine-approver is configured not to approve kubelet client CSRs
+	nodeClientCertDisabled bool
+	// capiMachineNamespaces are the namespaces whose CAPI machines have their CSRs approved by an available
+	// cluster-machine-approver, as set by its --apigroup and --machine-namespace flags
+	capiMachineNamespaces sets.Set[string]
+}
+
+// approvesCAPIMachines returns true when an available cluster-machine-approver approves the CSRs of the CAPI
+// machines of the namespace
+func (s machineApproverState) approvesCAPIMachines(namespace string) bool {
+	return s.capiMachineNamespaces.Has(namespace)
+}
+
+// machineApproverConfig is the part of the cluster-machine-approver configuration relevant to kubelet CSRs
+type machineApproverConfig struct {
+	NodeClientCert struct {
+		Disabled bool `json:"disabled,omitempty"`
+	} `json:"nodeClientCert,omitempty"`
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// detectMachineApprover looks up the cluster-machine-approver Deployments and their configuration
+func detectMachineApprover(ctx context.Context, c client.Client) (machineApproverState, error) {
+	state := machineApproverState{capiMachineNamespaces: sets.New[string]()}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := c.List(ctx, deployments, client.InNamespace(machineApproverNamespace)); err != nil {
+		return state, fmt.Errorf("failed to list machine-approver Deployments: %w", err)
+	}
+	for _, deployment := range deployments.Items {
+		available := deployment.Status.AvailableReplicas > 0
+		if deployment.Name == machineApproverDeployment {
+			state.installed = true
+			state.available = available
+		}
+		if namespace, ok := capiMachineNamespace(&deployment); ok && available {
+			state.capiMachineNamespaces.Insert(namespace)
+		}
+	}
+	if !state.installed && state.capiMachineNamespaces.Len() == 0 {
+		return state, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Name: machineApproverConfigMap, Namespace: machineApproverNamespace}, configMap)
+	if errors.IsNotFound(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to get machine-approver configuration: %w", err)
+	}
+
+	config := machineApproverConfig{}
+	if err := yaml.Unmarshal([]byte(configMap.Data[machineApproverConfigKey]), &config); err != nil {
+		return state, fmt.Errorf("failed to parse machine-approver configuration: %w", err)
+	}
+	state.nodeClientCertDisabled = config.NodeClientCert.Disabled
+
+	return state, nil
+}
+
+// capiMachineNamespace returns the namespace of the CAPI machines whose CSRs the cluster-machine-approver
+// Deployment approves. It returns false when the Deployment approves Machine API machines or does not name
+// the namespace of the machines.
+func capiMachineNamespace(deployment *appsv1.Deployment) (string, bool) {
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		flags := map[string]string{}
+		args := append(append([]string(nil), container.Command...), container.Args...)
+		for i, arg := range args {
+			name, value, found := strings.Cut(arg, "=")
+			if !found && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				value = args[i+1]
+			}
+			flags[name] = value
+		}
+		if flags[machineApproverAPIGroupFlag] == capiv1beta1.GroupVersion.Group && flags[machineApproverMachineNamespaceFlag] != "" {
+			return flags[machineApproverMachineNamespaceFlag], true
+		}
+	}
+	return "", false
+}
+
+// csrApproverMode returns the approver mode configured on the autoscaler. Machines that do not belong
+// to an OCIClusterAutoscaler are approved by the operator.
+func csrApproverMode(autoscaler *capiv1alpha1.OCIClusterAutoscaler) capiv1alpha1.CSRApproverMode {
+	if autoscaler == nil || autoscaler.Spec.CSRApproval.Mode == "" {
+		return capiv1alpha1.CSRApproverModeOperator
+	}
+	return autoscaler.Spec.CSRApproval.Mode
+}
+
+// csrApprovalStatus returns the condition reporting which controller approves the kubelet CSRs of the cluster
+// whose machines are in the namespace
+func csrApprovalStatus(mode capiv1alpha1.CSRApproverMode, namespace string, state machineApproverState) metav1.Condition {
+	switch mode {
+	case capiv1alpha1.CSRApproverModeDisabled:
+		return metav1.Condition{
+			Type:    csrApprovalCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  approvalDisabledReason,
+			Message: "Kubelet CSRs of the cluster machines are not approved by the operator",
+		}
+	case capiv1alpha1.CSRApproverModeDelegateToMachineApprover:
+		if !state.available && state.capiMachineNamespaces.Len() == 0 {
+			return metav1.Condition{
+				Type:    csrApprovalCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  machineApproverUnavailableReason,
+				Message: fmt.Sprintf("Approval is delegated but no cluster-machine-approver is available in namespace %s", machineApproverNamespace),
+			}
+		}
+		// The default cluster-machine-approver only approves the CSRs of Machine API machines
+		if !state.approvesCAPIMachines(namespace) {
+			return metav1.Condition{
+				Type:   csrApprovalCondition,
+				Status: metav1.ConditionFalse,
+				Reason: machineApproverNotCAPIAwareReason,
+				Message: fmt.Sprintf("Approval is delegated but no available cluster-machine-approver runs with %s=%s and %s=%s",
+					machineApproverAPIGroupFlag, capiv1beta1.GroupVersion.Group, machineApproverMachineNamespaceFlag, namespace),
+			}
+		}
+		message := "Kubelet CSRs of the cluster machines are approved by the cluster-machine-approver"
+		if state.nodeClientCertDisabled {
+			message += ", which is configured not to approve kubelet client CSRs"
+		}
+		return metav1.Condition{
+			Type:    csrApprovalCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  delegatedToMachineApproverReason,
+			Message: message,
+		}
+	default:
+		message := fmt.Sprintf("Kubelet CSRs of the cluster machines are approved by %s with reason %s", csrApproverIdentity, csrApprovedConditionReason)
+		if state.available {
+			message += "; the cluster-machine-approver is running as well and approves the CSRs of Machine API machines"
+		}
+		return metav1.Condition{
+			Type:    csrApprovalCondition,
+			Status:  metav1.ConditionTrue,
+			Reason:  approvedByOperatorReason,
+			Message: message,
+		}
+	}
+}
+
+// reconcileCSRApprovalStatus reports the configured approver mode and the cluster-machine-approver found in the cluster
+func (r *OCIClusterAutoscalerReconciler) reconcileCSRApprovalStatus(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	state, err := detectMachineApprover(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, csrApprovalStatus(csrApproverMode(autoscaler), capiNamespace(autoscaler), state))
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("CSR approver mode", func() {
+	It("should default to approval by the operator", func() {
+		Expect(csrApproverMode(nil)).To(Equal(capiv1alpha1.CSRApproverModeOperator))
+		Expect(csrApproverMode(&capiv1alpha1.OCIClusterAutoscaler{})).To(Equal(capiv1alpha1.CSRApproverModeOperator))
+
+		condition := csrApprovalStatus(capiv1alpha1.CSRApproverModeOperator, capiSystemNamespace, machineApproverState{installed: true, available: true})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(approvedByOperatorReason))
+		Expect(condition.Message).To(ContainSubstring(csrApprovedConditionReason))
+	})
+
+	It("should report delegation to a cluster-machine-approver of the CAPI machines of the namespace", func() {
+		state := machineApproverState{installed: true, available: true, nodeClientCertDisabled: true, capiMachineNamespaces: sets.New(capiSystemNamespace)}
+		condition := csrApprovalStatus(capiv1alpha1.CSRApproverModeDelegateToMachineApprover, capiSystemNamespace, state)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(delegatedToMachineApproverReason))
+		Expect(condition.Message).To(ContainSubstring("not to approve kubelet client CSRs"))
+	})
+
+	It("should not report delegation to a cluster-machine-approver of Machine API machines", func() {
+		state := machineApproverState{installed: true, available: true, capiMachineNamespaces: sets.New("other")}
+		condition := csrApprovalStatus(capiv1alpha1.CSRApproverModeDelegateToMachineApprover, capiSystemNamespace, state)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(machineApproverNotCAPIAwareReason))
+		Expect(condition.Message).To(ContainSubstring("--machine-namespace=capi-system"))
+	})
+
+	It("should report delegation to a missing cluster-machine-approver", func() {
+		condition := csrApprovalStatus(capiv1alpha1.CSRApproverModeDelegateToMachineApprover, capiSystemNamespace, machineApproverState{})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(machineApproverUnavailableReason))
+	})
+
+	It("should detect the available cluster-machine-approvers of CAPI machines", func() {
+		deployment := func(name string, available int32, args ...string) *appsv1.Deployment {
+			d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: machineApproverNamespace}}
+			d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "machine-approver-controller", Args: args}}
+			d.Status.AvailableReplicas = available
+			return d
+		}
+		c := newFakeClientBuilder().WithObjects(
+			deployment(machineApproverDeployment, 1, "--config=/var/run/configmaps/config/config.yaml"),
+			deployment("machine-approver-capi", 1, "--apigroup=cluster.x-k8s.io", "--machine-namespace", capiSystemNamespace),
+			deployment("machine-approver-unavailable", 0, "--apigroup=cluster.x-k8s.io", "--machine-namespace=other"),
+		).Build()
+
+		state, err := detectMachineApprover(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state.installed).To(BeTrue())
+		Expect(state.available).To(BeTrue())
+		Expect(state.approvesCAPIMachines(capiSystemNamespace)).To(BeTrue())
+		Expect(state.approvesCAPIMachines("other")).To(BeFalse())
+	})
+
+	It("should report disabled approval", func() {
+		condition := csrApprovalStatus(capiv1alpha1.CSRApproverModeDisabled, capiSystemNamespace, machineApproverState{available: true})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(approvalDisabledReason))
+	})
+})
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	machineProvisioningSecondsDesc = prometheus.NewDesc(
+		"oci_capi_operator_machine_provisioning_seconds",
+		"Time in seconds since creation of CAPI Machines that have not joined the cluster as a Node yet",
+		[]string{"namespace", "machine", "phase"}, nil,
+	)
+	pendingCSRAgeSecondsDesc = prometheus.NewDesc(
+		"oci_capi_operator_pending_csr_age_seconds",
+		"Time in seconds since creation of kubelet CertificateSigningRequests that are neither approved nor denied",
+		[]string{"csr", "signer"}, nil,
+	)
+)
+
+var (
+	csrApprovalThrottled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "oci_capi_operator_csr_approval_throttled",
+			Help: "Whether approval of kubelet CSRs is paused because the pending CSR or per machine approval limits are exceeded",
+		},
+		[]string{"cluster"},
+	)
+	csrApprovalsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oci_capi_operator_csr_approvals_total",
+			Help: "Number of kubelet CSRs approved, by signer and by whether they were requested by a new node or renew the certificate of an existing node",
+		},
+		[]string{"signer", "request"},
+	)
+	csrApprovalsThrottledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oci_capi_operator_csr_approvals_throttled_total",
+			Help: "Number of times a matching kubelet CSR was not approved because an approval limit was exceeded",
+		},
+		[]string{"cluster", "reason"},
+	)
+	janitorDeletionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oci_capi_operator_janitor_deleted_total",
+			Help: "Number of CSRs and Node objects of deleted machines garbage collected, by kind",
+		},
+		[]string{"cluster", "kind"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(csrApprovalThrottled, csrApprovalsTotal, csrApprovalsThrottledTotal, janitorDeletionsTotal)
+}
+
+// provisioningCollector exposes the age of machines and kubelet CSRs that are still waiting to
+// become part of the cluster. The values are computed on every scrape from the manager cache, which
+// holds the typed Machines of every namespace and the CSRs watched by the CSR approver, so scrapes
+// do not reach the API server.
+type provisioningCollector struct {
+	client client.Client
+}
+
+// registerProvisioningCollector registers the provisioning collector with the controller-runtime metrics registry
+func registerProvisioningCollector(c client.Client) error {
+	err := metrics.Registry.Register(&provisioningCollector{client: c})
+	if are := (prometheus.AlreadyRegisteredError{}); errors.As(err, &are) {
+		return nil
+	}
+	return err
+}
+
+// Describe implements prometheus.Collector
+func (c *provisioningCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- machineProvisioningSecondsDesc
+	ch <- pendingCSRAgeSecondsDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *provisioningCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	now := time.Now()
+
+	machines := &capiv1beta1.MachineList{}
+	if err := c.client.List(ctx, machines); err == nil {
+		for i := range machines.Items {
+			machine := &machines.Items[i]
+			if machine.Status.NodeRef != nil {
+				continue
+			}
+			switch capiv1beta1.MachinePhase(machine.Status.Phase) {
+			case capiv1beta1.MachinePhaseRunning, capiv1beta1.MachinePhaseDeleting, capiv1beta1.MachinePhaseDeleted, capiv1beta1.MachinePhaseFailed:
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(machineProvisioningSecondsDesc, prometheus.GaugeValue,
+				now.Sub(machine.CreationTimestamp.Time).Seconds(),
+				machine.Namespace, machine.Name, machine.Status.Phase)
+		}
+	}
+
+	csrs := &certificatesv1.CertificateSigningRequestList{}
+	if err := c.client.List(ctx, csrs); err == nil {
+		for i := range csrs.Items {
+			csr := &csrs.Items[i]
+			if !isKubeletCSR(csr) || isCSRApproved(csr) || isCSRDenied(csr) {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(pendingCSRAgeSecondsDesc, prometheus.GaugeValue,
+				now.Sub(csr.CreationTimestamp.Time).Seconds(),
+				csr.Name, csr.Spec.SignerName)
+		}
+	}
+}
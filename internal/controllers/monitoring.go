@@ -0,0 +1,429 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	clusterAutoscalerMetricsPort = 8085
+	capiManagerMetricsPort       = 8443
+
+	// clusterAutoscalerLocalMetricsPort is the loopback port the cluster-autoscaler serves its metrics on
+	// behind the metrics proxy
+	clusterAutoscalerLocalMetricsPort = 8086
+
+	clusterAutoscalerMetricsServiceName = "oci-cluster-autoscaler-metrics"
+	capiManagerMetricsServiceName       = "capi-controller-manager-metrics"
+	alertRulesName                      = "oci-capi-operator-alerts"
+
+	// capiManagerMetricsCertSecretName is the Secret the OpenShift service CA issues the serving certificate
+	// of the CAPI manager metrics Service into. It is mounted where the metrics server of the CAPI manager
+	// looks for its certificate, so that Prometheus can verify it against the service CA.
+	capiManagerMetricsCertSecretName = "capi-controller-manager-metrics-tls"
+	capiManagerMetricsCertDir        = "/tmp/k8s-metrics-server/serving-certs"
+
+	// clusterAutoscalerMetricsCertSecretName is the Secret the service CA issues the serving certificate of
+	// the cluster-autoscaler metrics proxy into
+	clusterAutoscalerMetricsCertSecretName = "oci-cluster-autoscaler-metrics-tls"
+	clusterAutoscalerMetricsCertDir        = "/etc/tls/private"
+
+	// metricsReaderName names the ServiceAccount, its token Secret and the ClusterRole and ClusterRoleBinding
+	// allowing it to read metrics. Prometheus authenticates to the metrics endpoints with its token.
+	metricsReaderName            = "oci-capi-metrics-reader"
+	metricsReaderTokenSecretName = "oci-capi-metrics-reader-token"
+
+	// servingCertSecretAnnotation asks the OpenShift service CA to issue a serving certificate for a Service
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+	// serviceCAConfigMapName is the ConfigMap holding the service CA bundle, which OpenShift creates in every namespace
+	serviceCAConfigMapName = "openshift-service-ca.crt"
+	serviceCAConfigMapKey  = "service-ca.crt"
+
+	// clusterMonitoringLabel opts a namespace in to be scraped by the OpenShift platform monitoring stack
+	clusterMonitoringLabel = "openshift.io/cluster-monitoring"
+	// platformPrometheusNamespace and platformPrometheusServiceAccount identify the platform Prometheus
+	platformPrometheusNamespace      = "openshift-monitoring"
+	platformPrometheusServiceAccount = "prometheus-k8s"
+)
+
+var (
+	serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+)
+
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=services,resourceNames=oci-cluster-autoscaler-metrics;capi-controller-manager-metrics,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=namespaces,resourceNames=capi-system,verbs=patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,resourceNames=oci-cluster-autoscaler-metrics;capi-controller-manager-metrics,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,resourceNames=oci-capi-operator-alerts,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=get;list;watch
+
+// monitoringObject returns an empty unstructured object of the given monitoring.coreos.com kind
+func monitoringObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// reconcileMonitoring creates the metrics Services, ServiceMonitors and alerting rules for the
+// cluster-autoscaler and the CAPI manager. It returns false without doing anything when the
+// monitoring.coreos.com CRDs are not installed.
+func (r *OCIClusterAutoscalerReconciler) reconcileMonitoring(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) (bool, error) {
+	installed, err := r.checkMonitoringInstallation(ctx)
+	if err != nil || !installed {
+		return false, err
+	}
+
+	if err := r.enableNamespaceMonitoring(ctx, autoscaler); err != nil {
+		return false, err
+	}
+
+	if err := r.createMetricsService(ctx, autoscaler, capiManagerMetricsServiceName,
+		map[string]string{"cluster.x-k8s.io/provider": "cluster-api", "control-plane": "controller-manager"}, capiManagerMetricsPort,
+		map[string]string{servingCertSecretAnnotation: capiManagerMetricsCertSecretName}); err != nil {
+		return false, err
+	}
+	if err := r.createMetricsReader(ctx, autoscaler); err != nil {
+		return false, err
+	}
+
+	if err := r.createServiceMonitor(ctx, autoscaler, clusterAutoscalerMetricsServiceName, secureMetricsEndpoint(clusterAutoscalerMetricsServiceName)); err != nil {
+		return false, err
+	}
+	if err := r.createServiceMonitor(ctx, autoscaler, capiManagerMetricsServiceName, secureMetricsEndpoint(capiManagerMetricsServiceName)); err != nil {
+		return false, err
+	}
+
+	if err := r.createAlertRules(ctx, autoscaler); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkMonitoringInstallation checks whether the ServiceMonitor and PrometheusRule CRDs exist
+func (r *OCIClusterAutoscalerReconciler) checkMonitoringInstallation(ctx context.Context) (bool, error) {
+	for _, name := range []string{"servicemonitors.monitoring.coreos.com", "prometheusrules.monitoring.coreos.com"} {
+		if installed, err := isCRDInstalled(ctx, r.Client, name); err != nil || !installed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// enableNamespaceMonitoring labels the CAPI namespace for platform monitoring and lets the
+// platform Prometheus discover the metrics endpoints in it
+func (r *OCIClusterAutoscalerReconciler) enableNamespaceMonitoring(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	// The label is applied by its own field manager so that it survives the namespace being
+	// re-applied without it
+	ns := partialObject(corev1.SchemeGroupVersion.WithKind("Namespace"), "", capiSystemNamespace)
+	ns.SetLabels(map[string]string{clusterMonitoringLabel: "true"})
+	err := applyAs(ctx, r.Client, ns, monitoringFieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to label namespace %s for monitoring: %w", capiSystemNamespace, err)
+	}
+
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      platformPrometheusServiceAccount,
+			Namespace: capiSystemNamespace,
+		},
+	}
+	setOwnerMetadata(role, autoscaler)
+	role.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"services", "endpoints", "pods"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+	err = r.apply(ctx, role)
+	if err != nil {
+		return fmt.Errorf("failed to create/update Prometheus Role: %w", err)
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      platformPrometheusServiceAccount,
+			Namespace: capiSystemNamespace,
+		},
+	}
+	setOwnerMetadata(roleBinding, autoscaler)
+	roleBinding.RoleRef = rbacv1.RoleRef{
+		APIGroup: "rbac.authorization.k8s.io",
+		Kind:     "Role",
+		Name:     platformPrometheusServiceAccount,
+	}
+	roleBinding.Subjects = []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      platformPrometheusServiceAccount,
+			Namespace: platformPrometheusNamespace,
+		},
+	}
+	err = r.apply(ctx, roleBinding)
+	if err != nil {
+		return fmt.Errorf("failed to create/update Prometheus RoleBinding: %w", err)
+	}
+	return nil
+}
+
+// createMetricsService creates or updates a Service exposing the metrics port of the pods matching selector
+func (r *OCIClusterAutoscalerReconciler) createMetricsService(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, name string, selector map[string]string, port int32, annotations map[string]string) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   capiSystemNamespace,
+			Annotations: annotations,
+		},
+	}
+	service.Labels = map[string]string{
+		"app.kubernetes.io/name":       name,
+		"app.kubernetes.io/managed-by": "oci-capi-operator",
+	}
+	setOwnerMetadata(service, autoscaler)
+	service.Spec.Selector = selector
+	service.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       "metrics",
+			Port:       port,
+			TargetPort: intstr.FromInt32(port),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+	err := r.apply(ctx, service)
+	if err != nil {
+		return fmt.Errorf("failed to create/update metrics Service %s: %w", name, err)
+	}
+	return nil
+}
+
+// createMetricsReader creates the ServiceAccount Prometheus authenticates as to the metrics endpoints, its
+// long-lived token Secret and its permission to read metrics
+func (r *OCIClusterAutoscalerReconciler) createMetricsReader(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsReaderName,
+			Namespace: capiSystemNamespace,
+		},
+	}
+	setOwnerMetadata(sa, autoscaler)
+	if err := r.apply(ctx, sa); err != nil {
+		return fmt.Errorf("failed to create/update metrics reader ServiceAccount: %w", err)
+	}
+
+	// The token is filled in by the token controller
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsReaderTokenSecretName,
+			Namespace: capiSystemNamespace,
+			Annotations: map[string]string{
+				corev1.ServiceAccountNameKey: metricsReaderName,
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	setOwnerMetadata(token, autoscaler)
+	if err := r.apply(ctx, token); err != nil {
+		return fmt.Errorf("failed to create/update metrics reader token Secret: %w", err)
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: metricsReaderName,
+		},
+	}
+	setOwnerMetadata(clusterRole, autoscaler)
+	clusterRole.Rules = []rbacv1.PolicyRule{
+		{
+			NonResourceURLs: []string{"/metrics"},
+			Verbs:           []string{"get"},
+		},
+	}
+	if err := r.apply(ctx, clusterRole); err != nil {
+		return fmt.Errorf("failed to create/update metrics reader ClusterRole: %w", err)
+	}
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: metricsReaderName,
+		},
+	}
+	setOwnerMetadata(clusterRoleBinding, autoscaler)
+	clusterRoleBinding.RoleRef = rbacv1.RoleRef{
+		APIGroup: "rbac.authorization.k8s.io",
+		Kind:     "ClusterRole",
+		Name:     metricsReaderName,
+	}
+	clusterRoleBinding.Subjects = []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      metricsReaderName,
+			Namespace: capiSystemNamespace,
+		},
+	}
+	if err := r.apply(ctx, clusterRoleBinding); err != nil {
+		return fmt.Errorf("failed to create/update metrics reader ClusterRoleBinding: %w", err)
+	}
+	return nil
+}
+
+// secureMetricsEndpoint returns the ServiceMonitor endpoint of the metrics Service with the given name. Its
+// certificate is issued by the service CA and the metrics reader token authenticates Prometheus.
+func secureMetricsEndpoint(service string) map[string]interface{} {
+	return map[string]interface{}{
+		"port":     "metrics",
+		"scheme":   "https",
+		"interval": "30s",
+		"authorization": map[string]interface{}{
+			"type": "Bearer",
+			"credentials": map[string]interface{}{
+				"name": metricsReaderTokenSecretName,
+				"key":  corev1.ServiceAccountTokenKey,
+			},
+		},
+		"tlsConfig": map[string]interface{}{
+			"ca": map[string]interface{}{
+				"configMap": map[string]interface{}{
+					"name": serviceCAConfigMapName,
+					"key":  serviceCAConfigMapKey,
+				},
+			},
+			"serverName": fmt.Sprintf("%s.%s.svc", service, capiSystemNamespace),
+		},
+	}
+}
+
+// serviceMonitor returns the ServiceMonitor scraping the metrics Service with the given name
+func serviceMonitor(name string, endpoint map[string]interface{}) *unstructured.Unstructured {
+	serviceMonitor := monitoringObject(serviceMonitorGVK)
+	serviceMonitor.SetName(name)
+	serviceMonitor.SetNamespace(capiSystemNamespace)
+	serviceMonitor.Object["spec"] = map[string]interface{}{
+		"endpoints": []interface{}{endpoint},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{capiSystemNamespace},
+		},
+		"selector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{
+				"app.kubernetes.io/name": name,
+			},
+		},
+	}
+	return serviceMonitor
+}
+
+// createServiceMonitor creates or updates a ServiceMonitor scraping the metrics Service with the given name
+func (r *OCIClusterAutoscalerReconciler) createServiceMonitor(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, name string, endpoint map[string]interface{}) error {
+	serviceMonitor := serviceMonitor(name, endpoint)
+	setOwnerMetadata(serviceMonitor, autoscaler)
+	err := r.apply(ctx, serviceMonitor)
+	if err != nil {
+		return fmt.Errorf("failed to create/update ServiceMonitor %s: %w", name, err)
+	}
+	return nil
+}
+
+// prometheusRule returns the PrometheusRule with the alerts for the managed components
+func prometheusRule() *unstructured.Unstructured {
+	rule := monitoringObject(prometheusRuleGVK)
+	rule.SetName(alertRulesName)
+	rule.SetNamespace(capiSystemNamespace)
+	rule.Object["spec"] = map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":  "oci-capi-operator",
+				"rules": alertRules(),
+			},
+		},
+	}
+	return rule
+}
+
+// createAlertRules creates or updates the PrometheusRule with the alerts for the managed components
+func (r *OCIClusterAutoscalerReconciler) createAlertRules(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	rule := prometheusRule()
+	setOwnerMetadata(rule, autoscaler)
+	err := r.apply(ctx, rule)
+	if err != nil {
+		return fmt.Errorf("failed to create/update PrometheusRule %s: %w", alertRulesName, err)
+	}
+	return nil
+}
+
+// alertRules returns the alerting rules for the cluster-autoscaler, the CAPI machines and the CSR approver
+func alertRules() []interface{} {
+	alert := func(name, expr, duration, summary, description string) interface{} {
+		return map[string]interface{}{
+			"alert": name,
+			"expr":  expr,
+			"for":   duration,
+			"labels": map[string]interface{}{
+				"severity": "warning",
+			},
+			"annotations": map[string]interface{}{
+				"summary":     summary,
+				"description": description,
+			},
+		}
+	}
+
+	return []interface{}{
+		alert("OCIClusterAutoscalerNodeGroupAtMax",
+			"cluster_autoscaler_node_group_target_count >= cluster_autoscaler_node_group_max_count",
+			"30m",
+			"Cluster autoscaler node group is at its maximum size",
+			"Node group {{ $labels.node_group }} has been at its maximum size for 30 minutes and cannot scale up any further.",
+		),
+		alert("OCIClusterAutoscalerUnschedulablePodsNoScaleUp",
+			"sum(cluster_autoscaler_unschedulable_pods_count) > 0 and sum(increase(cluster_autoscaler_scaled_up_nodes_total[15m])) == 0",
+			"15m",
+			"Unschedulable pods are not triggering a scale-up",
+			"There have been unschedulable pods for 15 minutes without the cluster autoscaler adding any node.",
+		),
+		alert("OCICAPIMachineProvisioningTimeout",
+			"max by (namespace, machine) (oci_capi_operator_machine_provisioning_seconds) > 1200",
+			"5m",
+			"CAPI machine is taking too long to provision",
+			"Machine {{ $labels.namespace }}/{{ $labels.machine }} was created more than 20 minutes ago and has not joined the cluster yet.",
+		),
+		alert("OCICAPICSRPendingTooLong",
+			"max by (csr) (oci_capi_operator_pending_csr_age_seconds) > 900",
+			"5m",
+			"Kubelet certificate signing request pending for too long",
+			"CertificateSigningRequest {{ $labels.csr }} has been pending for more than 15 minutes.",
+		),
+		alert("OCICAPICSRApprovalThrottled",
+			"max by (cluster) (oci_capi_operator_csr_approval_throttled) > 0",
+			"10m",
+			"Kubelet CSR approval is paused by the approval limits",
+			"Approval of kubelet CSRs for cluster {{ $labels.cluster }} has been paused for 10 minutes because too many CSRs are pending or a machine requested too many certificates. Check the CSRApprovalThrottled condition of the OCIClusterAutoscaler.",
+		),
+	}
+}
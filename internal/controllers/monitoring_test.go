@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("Monitoring", func() {
+	Describe("ServiceMonitors", func() {
+		endpoint := func(obj *unstructured.Unstructured) map[string]interface{} {
+			endpoints, found, err := unstructured.NestedSlice(obj.Object, "spec", "endpoints")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(endpoints).To(HaveLen(1))
+			return endpoints[0].(map[string]interface{})
+		}
+
+		It("should select the metrics Service in the CAPI namespace", func() {
+			monitor := serviceMonitor(clusterAutoscalerMetricsServiceName, secureMetricsEndpoint(clusterAutoscalerMetricsServiceName))
+			Expect(monitor.GroupVersionKind()).To(Equal(serviceMonitorGVK))
+			Expect(monitor.GetNamespace()).To(Equal(capiSystemNamespace))
+
+			name, _, _ := unstructured.NestedString(monitor.Object, "spec", "selector", "matchLabels", "app.kubernetes.io/name")
+			Expect(name).To(Equal(clusterAutoscalerMetricsServiceName))
+			namespaces, _, _ := unstructured.NestedStringSlice(monitor.Object, "spec", "namespaceSelector", "matchNames")
+			Expect(namespaces).To(ConsistOf(capiSystemNamespace))
+			Expect(endpoint(monitor)).To(HaveKeyWithValue("scheme", "https"))
+		})
+
+		It("should verify the CAPI manager certificate and authenticate with the metrics reader token", func() {
+			monitor := serviceMonitor(capiManagerMetricsServiceName, secureMetricsEndpoint(capiManagerMetricsServiceName))
+			ep := endpoint(monitor)
+			Expect(ep).To(HaveKeyWithValue("scheme", "https"))
+			Expect(ep).NotTo(HaveKey("bearerTokenFile"))
+
+			tlsConfig := ep["tlsConfig"].(map[string]interface{})
+			Expect(tlsConfig).NotTo(HaveKey("insecureSkipVerify"))
+			Expect(tlsConfig).To(HaveKeyWithValue("serverName", "capi-controller-manager-metrics.capi-system.svc"))
+			ca, _, _ := unstructured.NestedStringMap(tlsConfig, "ca", "configMap")
+			Expect(ca).To(Equal(map[string]string{"name": serviceCAConfigMapName, "key": serviceCAConfigMapKey}))
+
+			authorization := ep["authorization"].(map[string]interface{})
+			Expect(authorization).To(HaveKeyWithValue("type", "Bearer"))
+			credentials, _, _ := unstructured.NestedStringMap(authorization, "credentials")
+			Expect(credentials).To(Equal(map[string]string{"name": metricsReaderTokenSecretName, "key": corev1.ServiceAccountTokenKey}))
+		})
+	})
+
+	Describe("PrometheusRule", func() {
+		It("should group every alert with an expression, a duration and a severity", func() {
+			rule := prometheusRule()
+			Expect(rule.GroupVersionKind()).To(Equal(prometheusRuleGVK))
+			Expect(rule.GetName()).To(Equal(alertRulesName))
+			Expect(rule.GetNamespace()).To(Equal(capiSystemNamespace))
+
+			groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+			Expect(groups).To(HaveLen(1))
+			rules, _, _ := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+
+			var names []string
+			for _, r := range rules {
+				alert := r.(map[string]interface{})
+				names = append(names, alert["alert"].(string))
+				Expect(alert["expr"]).NotTo(BeEmpty())
+				Expect(alert["for"]).NotTo(BeEmpty())
+				severity, _, _ := unstructured.NestedString(alert, "labels", "severity")
+				Expect(severity).To(Equal("warning"))
+				summary, _, _ := unstructured.NestedString(alert, "annotations", "summary")
+				Expect(summary).NotTo(BeEmpty())
+			}
+			Expect(names).To(ConsistOf(
+				"OCIClusterAutoscalerNodeGroupAtMax",
+				"OCIClusterAutoscalerUnschedulablePodsNoScaleUp",
+				"OCICAPIMachineProvisioningTimeout",
+				"OCICAPICSRPendingTooLong",
+				"OCICAPICSRApprovalThrottled",
+			))
+		})
+	})
+
+	Describe("provisioning collector", func() {
+		created := metav1.NewTime(time.Now().Add(-time.Hour))
+
+		machine := func(namespace, name, phase string, nodeRef *corev1.ObjectReference) *capiv1beta1.Machine {
+			return &capiv1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: created},
+				Status:     capiv1beta1.MachineStatus{Phase: phase, NodeRef: nodeRef},
+			}
+		}
+
+		collect := func(c *provisioningCollector) map[*prometheus.Desc]int {
+			ch := make(chan prometheus.Metric, 10)
+			c.Collect(ch)
+			close(ch)
+
+			counts := map[*prometheus.Desc]int{}
+			for m := range ch {
+				counts[m.Desc()]++
+			}
+			return counts
+		}
+
+		It("should report provisioning machines of every namespace and pending kubelet CSRs", func() {
+			c := newFakeClientBuilder().WithObjects(
+				machine("capi-system", "provisioning", string(capiv1beta1.MachinePhaseProvisioning), nil),
+				machine("workload", "provisioned", string(capiv1beta1.MachinePhaseProvisioned), nil),
+				machine("workload", "running", string(capiv1beta1.MachinePhaseRunning), nil),
+				machine("workload", "joined", string(capiv1beta1.MachinePhaseProvisioned), &corev1.ObjectReference{Name: "node"}),
+				&certificatesv1.CertificateSigningRequest{
+					ObjectMeta: metav1.ObjectMeta{Name: "pending", CreationTimestamp: created},
+					Spec:       certificatesv1.CertificateSigningRequestSpec{SignerName: certificatesv1.KubeletServingSignerName},
+				},
+			).Build()
+
+			counts := collect(&provisioningCollector{client: c})
+			Expect(counts[machineProvisioningSecondsDesc]).To(Equal(2))
+			Expect(counts[pendingCSRAgeSecondsDesc]).To(Equal(1))
+		})
+	})
+})
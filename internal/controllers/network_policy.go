@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// NetworkPolicies created in each CAPI controller namespace
+	defaultDenyPolicyName   = "oci-capi-default-deny"
+	allowWebhooksPolicyName = "oci-capi-allow-webhooks"
+	allowMetricsPolicyName  = "oci-capi-allow-metrics"
+	allowEgressPolicyName   = "oci-capi-allow-egress"
+
+	webhookServerPort = 9443
+	apiServerPort     = 6443
+	ociEndpointPort   = 443
+	clusterDNSPort    = 5353
+
+	// hostNetworkPolicyGroup selects the host network, which the API server calls webhooks from, on OpenShift
+	hostNetworkPolicyGroup = "policy-group.network.openshift.io/host-network"
+
+	namespaceNameLabel  = "kubernetes.io/metadata.name"
+	clusterDNSNamespace = "openshift-dns"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,resourceNames=oci-capi-default-deny;oci-capi-allow-webhooks;oci-capi-allow-metrics;oci-capi-allow-egress,verbs=update;patch;delete
+
+// networkPolicyNames are the NetworkPolicies created in each CAPI controller namespace
+var networkPolicyNames = []string{defaultDenyPolicyName, allowWebhooksPolicyName, allowMetricsPolicyName, allowEgressPolicyName}
+
+// metricsPorts returns the metrics ports of the controllers running in the namespaces with NetworkPolicies
+func metricsPorts() map[string][]int32 {
+	return map[string][]int32{
+		capiSystemNamespace: {capiManagerMetricsPort, clusterAutoscalerMetricsPort},
+		capociNamespace:     {capiManagerMetricsPort},
+	}
+}
+
+// policyPort returns the network policy port of the protocol and port number
+func policyPort(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+	number := intstr.FromInt32(port)
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &number}
+}
+
+// tcpPorts returns the network policy ports of the TCP ports
+func tcpPorts(ports ...int32) []networkingv1.NetworkPolicyPort {
+	policyPorts := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		policyPorts = append(policyPorts, policyPort(corev1.ProtocolTCP, port))
+	}
+	return policyPorts
+}
+
+// networkPolicies returns the NetworkPolicies of a CAPI controller namespace: everything is denied except
+// webhook calls from the API server, which runs on the host network, metrics scraping by the platform
+// Prometheus, DNS, and egress to the API server and to the OCI endpoints over HTTPS
+func networkPolicies(namespace string, config *capiv1alpha1.NetworkPolicyConfig) []*networkingv1.NetworkPolicy {
+	policy := func(name string, types ...networkingv1.PolicyType) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{},
+				PolicyTypes: types,
+			},
+		}
+	}
+
+	defaultDeny := policy(defaultDenyPolicyName, networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress)
+
+	webhooks := policy(allowWebhooksPolicyName, networkingv1.PolicyTypeIngress)
+	webhooks.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{hostNetworkPolicyGroup: ""}}},
+			},
+			Ports: tcpPorts(webhookServerPort),
+		},
+	}
+
+	metrics := policy(allowMetricsPolicyName, networkingv1.PolicyTypeIngress)
+	metrics.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+		{
+			From: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: platformPrometheusNamespace}}},
+			},
+			Ports: tcpPorts(metricsPorts()[namespace]...),
+		},
+	}
+
+	cidrs := config.OCIEndpointCIDRs
+	if len(cidrs) == 0 {
+		cidrs = []string{"0.0.0.0/0"}
+	}
+	var ociEndpoints []networkingv1.NetworkPolicyPeer
+	for _, cidr := range cidrs {
+		ociEndpoints = append(ociEndpoints, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+
+	egress := policy(allowEgressPolicyName, networkingv1.PolicyTypeEgress)
+	egress.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{
+		{
+			To: []networkingv1.NetworkPolicyPeer{
+				{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: clusterDNSNamespace}}},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{
+				policyPort(corev1.ProtocolUDP, clusterDNSPort),
+				policyPort(corev1.ProtocolTCP, clusterDNSPort),
+			},
+		},
+		{
+			// The API server runs on the host network, its addresses are not known in advance
+			Ports: tcpPorts(apiServerPort),
+		},
+		{
+			To:    ociEndpoints,
+			Ports: tcpPorts(ociEndpointPort),
+		},
+	}
+
+	return []*networkingv1.NetworkPolicy{defaultDeny, webhooks, metrics, egress}
+}
+
+// reconcileNetworkPolicies creates the NetworkPolicies of the CAPI and CAPOCI namespaces, or deletes them
+// when they are disabled. It returns whether the NetworkPolicies are deployed.
+func (r *OCIClusterAutoscalerReconciler) reconcileNetworkPolicies(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) (bool, error) {
+	config := &autoscaler.Spec.NetworkPolicy
+	for _, namespace := range []string{capiSystemNamespace, capociNamespace} {
+		if config.Disabled {
+			for _, name := range networkPolicyNames {
+				policy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+				if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+					return false, fmt.Errorf("failed to delete NetworkPolicy %s/%s: %w", namespace, name, err)
+				}
+			}
+			continue
+		}
+
+		for _, policy := range networkPolicies(namespace, config) {
+			setOwnerMetadata(policy, autoscaler)
+			if err := r.apply(ctx, policy); err != nil {
+				return false, fmt.Errorf("failed to create/update NetworkPolicy %s/%s: %w", namespace, policy.Name, err)
+			}
+		}
+	}
+	return !config.Disabled, nil
+}
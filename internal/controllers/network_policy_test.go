@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("Network policies", func() {
+	byName := func(policies []*networkingv1.NetworkPolicy) map[string]*networkingv1.NetworkPolicy {
+		named := map[string]*networkingv1.NetworkPolicy{}
+		for _, policy := range policies {
+			named[policy.Name] = policy
+		}
+		return named
+	}
+
+	It("should deny all traffic of the namespace by default", func() {
+		policies := byName(networkPolicies(capociNamespace, &capiv1alpha1.NetworkPolicyConfig{}))
+		Expect(policies).To(HaveLen(len(networkPolicyNames)))
+
+		deny := policies[defaultDenyPolicyName]
+		Expect(deny.Namespace).To(Equal(capociNamespace))
+		Expect(deny.Spec.PodSelector.MatchLabels).To(BeEmpty())
+		Expect(deny.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+		Expect(deny.Spec.Ingress).To(BeEmpty())
+		Expect(deny.Spec.Egress).To(BeEmpty())
+	})
+
+	It("should allow scraping the metrics ports of the namespace from the platform monitoring", func() {
+		metrics := byName(networkPolicies(capiSystemNamespace, &capiv1alpha1.NetworkPolicyConfig{}))[allowMetricsPolicyName]
+		Expect(metrics.Spec.Ingress).To(HaveLen(1))
+		Expect(metrics.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels).To(HaveKeyWithValue(namespaceNameLabel, platformPrometheusNamespace))
+		Expect(metrics.Spec.Ingress[0].Ports).To(ConsistOf(
+			HaveField("Port", HaveValue(Equal(intstr.FromInt32(capiManagerMetricsPort)))),
+			HaveField("Port", HaveValue(Equal(intstr.FromInt32(clusterAutoscalerMetricsPort)))),
+		))
+	})
+
+	It("should restrict the HTTPS egress to the OCI endpoints", func() {
+		egress := byName(networkPolicies(capociNamespace, &capiv1alpha1.NetworkPolicyConfig{}))[allowEgressPolicyName]
+		Expect(egress.Spec.Egress).To(ContainElement(HaveField("To", ConsistOf(HaveField("IPBlock.CIDR", "0.0.0.0/0")))))
+
+		config := &capiv1alpha1.NetworkPolicyConfig{OCIEndpointCIDRs: []string{"134.70.0.0/16", "138.1.0.0/16"}}
+		egress = byName(networkPolicies(capociNamespace, config))[allowEgressPolicyName]
+		Expect(egress.Spec.Egress).To(ContainElement(HaveField("To", ConsistOf(
+			HaveField("IPBlock.CIDR", "134.70.0.0/16"),
+			HaveField("IPBlock.CIDR", "138.1.0.0/16"),
+		))))
+		Expect(egress.Spec.Egress).To(ContainElement(HaveField("Ports", ConsistOf(HaveField("Port", HaveValue(Equal(intstr.FromInt32(apiServerPort))))))))
+	})
+})
@@ -19,45 +19,80 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-openapi/swag"
+	configv1 "github.com/openshift/api/config/v1"
+	securityv1 "github.com/openshift/api/security/v1"
 	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
 )
 
 // OCIClusterAutoscalerReconciler reconciles a OCIClusterAutoscaler object
 type OCIClusterAutoscalerReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// APIReader reads the objects the manager cache does not hold, such as the credential Secrets
+	// referenced by the OCIClusterAutoscalers
+	APIReader client.Reader
+
+	// TLSProfile is the TLS security profile of the cluster, applied to the arguments of the CAPI
+	// controllers and of the cluster-autoscaler metrics proxy. It is nil on clusters without a TLS
+	// security profile.
+	TLSProfile *configv1.TLSProfileSpec
+
+	// OperatorNamespace is the namespace the operator runs in. It holds the user principal Secrets of the
+	// clusters, the only Secrets the operator writes whose names are not fixed.
+	OperatorNamespace string
+
+	// secretWatches watches the credential Secrets of the namespaces holding OCIClusterAutoscalers
+	secretWatches *secretNamespaceWatches
 }
 
 // +kubebuilder:rbac:groups=capi.openshift.io,resources=ociclusterautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=capi.openshift.io,resources=ociclusterautoscalers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=capi.openshift.io,resources=ociclusterautoscalers/finalizers,verbs=update
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=apps,resources=deployments,resourceNames=oci-cluster-autoscaler;capi-controller-manager;capoci-controller-manager,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts;secrets;configmaps,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,resourceNames=oci-cluster-autoscaler;oci-capi-metrics-reader,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,resourceNames=oci-credentials;oci-capi-metrics-reader-token,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,resourceNames=cluster-autoscaler-priority-expander,verbs=update;patch;delete
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=oci-capi,verbs=update;patch;delete
+// The objects of the CAPI cluster are named after the cluster and its node groups, which only the
+// OCIClusterAutoscalers define, so they cannot be restricted by name. The operator applies and deletes them
+// but never updates them. The user principal Secrets are named after the cluster as well and are kept in the
+// namespace of the operator, which is the only namespace it may change any Secret in.
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machinedeployments,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=ociclusters;ocimachinetemplates;ociclusteridentities,verbs=get;list;watch;create;patch;delete
+// +kubebuilder:rbac:groups=core,namespace=system,resources=secrets,verbs=patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -93,16 +128,25 @@ func (r *OCIClusterAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl
 		}
 	} else {
 		if controllerutil.ContainsFinalizer(autoscaler, finalizerName) {
-			// Perform cleanup
-			if err := r.cleanup(ctx, autoscaler); err != nil {
+			// Delete the managed objects before letting the autoscaler go
+			remaining, err := r.cleanup(ctx, autoscaler)
+			if err != nil {
 				return ctrl.Result{}, err
 			}
+			if remaining {
+				return ctrl.Result{RequeueAfter: cleanupRetryDelay}, nil
+			}
 			controllerutil.RemoveFinalizer(autoscaler, finalizerName)
 			return ctrl.Result{}, r.Update(ctx, autoscaler)
 		}
 		return ctrl.Result{}, nil
 	}
 
+	// Watch the credential Secrets of the namespace of the autoscaler
+	if err := r.secretWatches.watch(autoscaler.Namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile the OCI CAPI stack
 	result, err := r.reconcileOCICapiStack(ctx, autoscaler)
 	if err != nil {
@@ -158,13 +202,13 @@ func (r *OCIClusterAutoscalerReconciler) reconcileOCICapiStack(ctx context.Conte
 		return ctrl.Result{}, err
 	}
 
-	// Step 3: Create OCI credentials secret
-	if err := r.createOCICredentialsSecret(ctx, autoscaler); err != nil {
-		logger.Error(err, "Failed to create OCI credentials secret")
+	// Step 3: Propagate the OCI credentials to CAPOCI
+	if err := r.reconcileOCICredentials(ctx, autoscaler); err != nil {
+		logger.Error(err, "Failed to propagate OCI credentials")
 		return ctrl.Result{}, err
 	}
 
-	// Step 4: Check if CAPI CRDs exist (assuming clusterctl is used externally)
+	// Step 4: Check if CAPI and CAPOCI CRDs exist (assuming clusterctl is used externally)
 	capiInstalled, err := r.checkCAPIInstallation(ctx)
 	if err != nil {
 		logger.Error(err, "Failed to check CAPI installation")
@@ -177,27 +221,89 @@ func (r *OCIClusterAutoscalerReconciler) reconcileOCICapiStack(ctx context.Conte
 		return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
 	}
 
-	// Step 5: Deploy cluster-autoscaler
+	// Step 5: Create the OCICluster with its OCIClusterIdentity and the Cluster
+	if err := r.reconcileCAPICluster(ctx, autoscaler); err != nil {
+		logger.Error(err, "Failed to create the CAPI cluster")
+		return ctrl.Result{}, err
+	}
+
+	// Step 6: Create the OCIMachineTemplate and MachineDeployment of every node group
+	if err := r.reconcileNodeGroups(ctx, autoscaler); err != nil {
+		logger.Error(err, "Failed to create the node groups")
+		return ctrl.Result{}, err
+	}
+
+	// Step 7: Deploy cluster-autoscaler
 	if err := r.deployClusterAutoscaler(ctx, autoscaler); err != nil {
 		logger.Error(err, "Failed to deploy cluster-autoscaler")
 		return ctrl.Result{}, err
 	}
 	autoscaler.Status.ClusterAutoscalerDeployed = true
 
-	// Step 6: Create additional RBAC for cluster-autoscaler
+	// Step 8: Create additional RBAC for cluster-autoscaler
 	if err := r.createClusterAutoscalerRBAC(ctx, autoscaler); err != nil {
 		logger.Error(err, "Failed to create cluster-autoscaler RBAC")
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{RequeueAfter: time.Minute * 10}, nil
+	// Step 9: Apply the capacity schedules of the node groups
+	scheduleRequeue, err := r.reconcileCapacitySchedules(ctx, autoscaler, time.Now())
+	if err != nil {
+		logger.Error(err, "Failed to apply capacity schedules")
+		return ctrl.Result{}, err
+	}
+
+	// Step 10: Create metrics Services, ServiceMonitors and alerts if the monitoring CRDs exist
+	monitoringConfigured, err := r.reconcileMonitoring(ctx, autoscaler)
+	if err != nil {
+		logger.Error(err, "Failed to configure monitoring")
+		return ctrl.Result{}, err
+	}
+	autoscaler.Status.MonitoringConfigured = monitoringConfigured
+
+	// Step 11: Restrict the traffic of the CAPI controller namespaces
+	networkPoliciesApplied, err := r.reconcileNetworkPolicies(ctx, autoscaler)
+	if err != nil {
+		logger.Error(err, "Failed to configure NetworkPolicies")
+		return ctrl.Result{}, err
+	}
+	autoscaler.Status.NetworkPoliciesApplied = networkPoliciesApplied
+
+	// Step 12: Report which controller approves the kubelet CSRs of the cluster machines
+	if err := r.reconcileCSRApprovalStatus(ctx, autoscaler); err != nil {
+		logger.Error(err, "Failed to detect the cluster-machine-approver")
+		return ctrl.Result{}, err
+	}
+
+	// Step 13: Apply the TLS security profile of the cluster to CAPI and CAPOCI
+	if err := r.applyTLSProfile(ctx); err != nil {
+		logger.Error(err, "Failed to apply the TLS security profile to the CAPI controllers")
+		return ctrl.Result{}, err
+	}
+
+	// Step 14: Check that the CAPI controller pods were admitted under the oci-capi SCC
+	if err := r.checkSCCAdmission(ctx, autoscaler); err != nil {
+		logger.Error(err, "Failed to check the SCC of the CAPI controller pods")
+		return ctrl.Result{}, err
+	}
+
+	requeueAfter := time.Minute * 10
+	if scheduleRequeue > 0 && scheduleRequeue < requeueAfter {
+		requeueAfter = scheduleRequeue
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
+// ensureNamespaces creates the namespaces of the controllers and of the CAPI cluster when they are
+// missing. Existing namespaces are left alone, as the operator may only patch the CAPI namespace.
 func (r *OCIClusterAutoscalerReconciler) ensureNamespaces(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
 	namespaces := []string{
-		"cluster-api-provider-oci-system",
+		capociNamespace,
 		capiSystemNamespace,
 	}
+	if namespace := capiNamespace(autoscaler); namespace != capiSystemNamespace {
+		namespaces = append(namespaces, namespace)
+	}
 
 	for _, name := range namespaces {
 		ns := &corev1.Namespace{
@@ -205,92 +311,55 @@ func (r *OCIClusterAutoscalerReconciler) ensureNamespaces(ctx context.Context, a
 				Name: name,
 			},
 		}
-		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, ns, func() error {
-			return nil
-		})
-		if err != nil {
+		err := r.Create(ctx, ns)
+		if err != nil && !errors.IsAlreadyExists(err) {
 			return fmt.Errorf("failed to ensure namespace %s: %w", name, err)
 		}
 	}
 	return nil
 }
 
-func (r *OCIClusterAutoscalerReconciler) cleanup(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
-	// Cleanup resources created by the operator
-	// This would include removing deployments, RBAC, secrets, etc.
-	return nil
-}
-
 func (r *OCIClusterAutoscalerReconciler) createSecurityContextConstraintsCAPI(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
 	// This would create the SCC needed for CAPI components
 	// Implementation depends on OpenShift security API
 	return nil
 }
 
-func (r *OCIClusterAutoscalerReconciler) createOCICredentialsSecret(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
-	// Get private key from referenced secret first
-	privateKeySecret := &corev1.Secret{}
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      autoscaler.Spec.OCI.PrivateKeySecretRef.Name,
-		Namespace: autoscaler.Namespace,
-	}, privateKeySecret)
-	if err != nil {
-		return fmt.Errorf("failed to get private key secret: %w", err)
+func (r *OCIClusterAutoscalerReconciler) checkCAPIInstallation(ctx context.Context) (bool, error) {
+	// Check if the CAPI and CAPOCI CRDs of the cluster objects exist
+	for _, name := range []string{"clusters.cluster.x-k8s.io", "ociclusters.infrastructure.cluster.x-k8s.io"} {
+		if installed, err := isCRDInstalled(ctx, r.Client, name); err != nil || !installed {
+			return false, nil
+		}
 	}
+	return true, nil
+}
 
-	keyName := autoscaler.Spec.OCI.PrivateKeySecretRef.Key
-	if keyName == "" {
-		keyName = "private_key"
+// isCRDInstalled returns true if the CRD exists. Only the metadata of the CRDs is read, so that their
+// schemas are not held in the cache.
+func isCRDInstalled(ctx context.Context, c client.Reader, name string) (bool, error) {
+	crd := &metav1.PartialObjectMetadata{}
+	crd.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+	err := c.Get(ctx, types.NamespacedName{Name: name}, crd)
+	if errors.IsNotFound(err) {
+		return false, nil
 	}
-	privateKey, exists := privateKeySecret.Data[keyName]
-	if !exists {
-		return fmt.Errorf("private key not found in secret %s with key %s", autoscaler.Spec.OCI.PrivateKeySecretRef.Name, keyName)
-	}
-
-	// Create or update secret with OCI credentials for CAPI
-	secretName := "oci-credentials"
-	namespace := "cluster-api-provider-oci-system"
-
-	secret := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      secretName,
-			Namespace: namespace,
-		},
+	if err != nil {
+		return false, fmt.Errorf("failed to get CRD %s: %w", name, err)
 	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
-		secret.Type = corev1.SecretTypeOpaque
-		secret.Data = map[string][]byte{
-			"tenancy":     []byte(autoscaler.Spec.OCI.TenancyID),
-			"user":        []byte(autoscaler.Spec.OCI.UserID),
-			"region":      []byte(autoscaler.Spec.OCI.Region),
-			"fingerprint": []byte(autoscaler.Spec.OCI.Fingerprint),
-			"key":         privateKey,
-		}
-		return controllerutil.SetControllerReference(autoscaler, secret, r.Scheme)
-	})
-
-	return err
-}
-
-func (r *OCIClusterAutoscalerReconciler) checkCAPIInstallation(ctx context.Context) (bool, error) {
-	// Check if CAPI CRDs exist
-	crd := &apiextensionsv1.CustomResourceDefinition{}
-	err := r.Get(ctx, types.NamespacedName{Name: "clusters.cluster.x-k8s.io"}, crd)
-	return err == nil, nil
+	return true, nil
 }
 
 func (r *OCIClusterAutoscalerReconciler) deployClusterAutoscaler(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
 	// Create or update service account
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "oci-cluster-autoscaler",
+			Name:      clusterAutoscalerName,
 			Namespace: capiSystemNamespace,
 		},
 	}
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, sa, func() error {
-		return controllerutil.SetControllerReference(autoscaler, sa, r.Scheme)
-	})
+	setOwnerMetadata(sa, autoscaler)
+	err := r.apply(ctx, sa)
 	if err != nil {
 		return err
 	}
@@ -301,104 +370,107 @@ func (r *OCIClusterAutoscalerReconciler) deployClusterAutoscaler(ctx context.Con
 		image = autoscaler.Spec.ClusterAutoscaler.Image
 	}
 
+	// Create or update the priority expander configuration
+	if err := r.reconcilePriorityExpanderConfig(ctx, autoscaler); err != nil {
+		return err
+	}
+
+	command := []string{
+		"./cluster-autoscaler",
+		"--v=4",
+		"--stderrthreshold=info",
+		"--cloud-provider=clusterapi",
+		"--namespace=" + capiSystemNamespace,
+		"--clusterapi-cloud-config-authoritative",
+		"--node-group-auto-discovery=clusterapi:namespace=" + capiNamespace(autoscaler) + ",clusterName=" + clusterName(autoscaler),
+		fmt.Sprintf("--address=127.0.0.1:%d", clusterAutoscalerLocalMetricsPort),
+		"--emit-per-nodegroup-metrics",
+	}
+	if hasNodeGroupPriorities(autoscaler) {
+		command = append(command, "--expander=priority")
+	}
+
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "oci-cluster-autoscaler",
+			Name:      clusterAutoscalerName,
 			Namespace: capiSystemNamespace,
 		},
 	}
 
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
-		deployment.Spec = appsv1.DeploymentSpec{
-			Replicas: swag.Int32(1),
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app": "oci-cluster-autoscaler",
+	setOwnerMetadata(deployment, autoscaler)
+	deployment.Spec = appsv1.DeploymentSpec{
+		Replicas: swag.Int32(1),
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app": clusterAutoscalerName,
+			},
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					"app": clusterAutoscalerName,
 				},
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"app": "oci-cluster-autoscaler",
+			Spec: corev1.PodSpec{
+				ServiceAccountName: clusterAutoscalerName,
+				Containers: []corev1.Container{
+					{
+						Name:    "cluster-autoscaler",
+						Image:   image,
+						Command: command,
 					},
+					clusterAutoscalerMetricsProxy(r.TLSProfile),
 				},
-				Spec: corev1.PodSpec{
-					ServiceAccountName: "oci-cluster-autoscaler",
-					Containers: []corev1.Container{
-						{
-							Name:  "cluster-autoscaler",
-							Image: image,
-							Command: []string{
-								"./cluster-autoscaler",
-								"--v=4",
-								"--stderrthreshold=info",
-								"--cloud-provider=clusterapi",
-								"--namespace=" + capiSystemNamespace,
-								"--clusterapi-cloud-config-authoritative",
-								"--node-group-auto-discovery=clusterapi:namespace=" + capiSystemNamespace,
-							},
+				Volumes: []corev1.Volume{
+					{
+						Name: "metrics-cert",
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{SecretName: clusterAutoscalerMetricsCertSecretName},
 						},
 					},
 				},
 			},
-		}
-		return controllerutil.SetControllerReference(autoscaler, deployment, r.Scheme)
-	})
-
-	return err
-}
-
-func (r *OCIClusterAutoscalerReconciler) createClusterAutoscalerRBAC(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
-	// Create or update ClusterRole for additional CAPI permissions
-	clusterRole := &rbacv1.ClusterRole{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "oci-cluster-autoscaler-extra",
 		},
 	}
+	if err := r.apply(ctx, deployment); err != nil {
+		return err
+	}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, clusterRole, func() error {
-		clusterRole.Rules = []rbacv1.PolicyRule{
+	// The Service is created with the Deployment, as the serving certificate of the metrics proxy is only
+	// issued once it exists
+	return r.createMetricsService(ctx, autoscaler, clusterAutoscalerMetricsServiceName,
+		map[string]string{"app": clusterAutoscalerName}, clusterAutoscalerMetricsPort,
+		map[string]string{servingCertSecretAnnotation: clusterAutoscalerMetricsCertSecretName})
+}
+
+// clusterAutoscalerMetricsProxy returns the kube-rbac-proxy container serving the metrics of the
+// cluster-autoscaler over HTTPS with the TLS profile of the cluster. The cluster-autoscaler only serves
+// plain HTTP, so it listens on the loopback interface behind the proxy.
+func clusterAutoscalerMetricsProxy(profile *configv1.TLSProfileSpec) corev1.Container {
+	return corev1.Container{
+		Name:  "kube-rbac-proxy",
+		Image: KubeRBACProxyImage,
+		Args: withTLSArgs([]string{
+			fmt.Sprintf("--secure-listen-address=0.0.0.0:%d", clusterAutoscalerMetricsPort),
+			fmt.Sprintf("--upstream=http://127.0.0.1:%d/", clusterAutoscalerLocalMetricsPort),
+			"--tls-cert-file=" + clusterAutoscalerMetricsCertDir + "/" + corev1.TLSCertKey,
+			"--tls-private-key-file=" + clusterAutoscalerMetricsCertDir + "/" + corev1.TLSPrivateKeyKey,
+		}, profile),
+		Ports: []corev1.ContainerPort{
 			{
-				APIGroups: []string{"infrastructure.cluster.x-k8s.io"},
-				Resources: []string{"*"},
-				Verbs:     []string{"get", "list", "watch", "update"},
+				ContainerPort: clusterAutoscalerMetricsPort,
+				Name:          "metrics",
+				Protocol:      corev1.ProtocolTCP,
 			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
 			{
-				APIGroups: []string{"cluster.x-k8s.io"},
-				Resources: []string{"*"},
-				Verbs:     []string{"get", "list", "watch", "update"},
+				Name:      "metrics-cert",
+				MountPath: clusterAutoscalerMetricsCertDir,
+				ReadOnly:  true,
 			},
-		}
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	// Create or update ClusterRoleBinding
-	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "oci-cluster-autoscaler-extra",
 		},
 	}
-
-	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, clusterRoleBinding, func() error {
-		clusterRoleBinding.RoleRef = rbacv1.RoleRef{
-			APIGroup: "rbac.authorization.k8s.io",
-			Kind:     "ClusterRole",
-			Name:     "oci-cluster-autoscaler-extra",
-		}
-		clusterRoleBinding.Subjects = []rbacv1.Subject{
-			{
-				Kind:      "ServiceAccount",
-				Name:      "oci-cluster-autoscaler",
-				Namespace: capiSystemNamespace,
-			},
-		}
-		return nil
-	})
-
-	return err
 }
 
 func validate(instance *capiv1alpha1.OCIClusterAutoscaler) error {
@@ -413,16 +485,165 @@ func validateAutoscalerSpec(spec *capiv1alpha1.OCIClusterAutoscalerSpec) error {
 		return fmt.Errorf("minNodes [%d] must be less than or equal to maxNodes [%d]", spec.Autoscaling.MinNodes, spec.Autoscaling.MaxNodes)
 	}
 
+	if spec.OCI.CredentialsConfigRef == nil && (spec.OCI.TenancyID == "" || spec.OCI.Region == "") {
+		return fmt.Errorf("tenancyId and region are required when credentialsConfigRef is not set")
+	}
+
+	if err := validateOCIAuth(&spec.OCI); err != nil {
+		return err
+	}
+
 	if spec.Autoscaling.Shape == "" {
 		return fmt.Errorf("shape is required")
 	}
 
+	names := map[string]bool{}
+	for _, group := range spec.Autoscaling.NodeGroups {
+		if names[group.Name] {
+			return fmt.Errorf("node group name %q is not unique", group.Name)
+		}
+		names[group.Name] = true
+
+		if group.MinNodes > group.MaxNodes {
+			return fmt.Errorf("node group %s: minNodes [%d] must be less than or equal to maxNodes [%d]", group.Name, group.MinNodes, group.MaxNodes)
+		}
+
+		if group.Shape == "" {
+			return fmt.Errorf("node group %s: shape is required", group.Name)
+		}
+
+		if err := validateCapacitySchedules(group.Schedules); err != nil {
+			return fmt.Errorf("node group %s: %w", group.Name, err)
+		}
+	}
+
+	if err := validateCapacitySchedules(spec.Autoscaling.Schedules); err != nil {
+		return err
+	}
+
+	for _, cidr := range spec.NetworkPolicy.OCIEndpointCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("networkPolicy.ociEndpointCIDRs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateCapacitySchedules(schedules []capiv1alpha1.CapacitySchedule) error {
+	names := map[string]bool{}
+	for i := range schedules {
+		if names[schedules[i].Name] {
+			return fmt.Errorf("schedule name %q is not unique", schedules[i].Name)
+		}
+		names[schedules[i].Name] = true
+
+		if _, _, err := parseCapacitySchedule(&schedules[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OCIClusterAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := registerProvisioningCollector(mgr.GetClient()); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &capiv1alpha1.OCIClusterAutoscaler{},
+		credentialSecretsIndex, indexCredentialSecrets); err != nil {
+		return err
+	}
+
+	// Objects with a status are only re-reconciled when their spec, labels or annotations change
+	specChanged := builder.WithPredicates(predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	))
+	enqueueOwner := handler.EnqueueRequestsFromMapFunc(requestForOwnerLabels)
+
+	// Only the Secrets, Deployments and CRDs the operator reads are held, as metadata where their content
+	// is not needed. Objects the operator does not own but reads, such as the controllers installed by
+	// clusterctl, map to every OCIClusterAutoscaler. Namespaces are not watched since the operator only
+	// ensures that they exist, and the APIServer TLS profile is watched by the TLSProfileWatcher, which
+	// restarts the operator.
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&capiv1alpha1.OCIClusterAutoscaler{}).
-		Complete(r)
+		Watches(&capiv1alpha1.OCIClusterAutoscaler{}, handler.EnqueueRequestsFromMapFunc(r.requestsForRemainingAutoscalers),
+			builder.WithPredicates(autoscalerDeleted)).
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(r.requestsForSharedObject(schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"})), specChanged).
+		Watches(&corev1.ServiceAccount{}, enqueueOwner).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.requestsForSharedObject(schema.GroupKind{Kind: "ConfigMap"}))).
+		Watches(&corev1.Service{}, enqueueOwner).
+		Watches(&corev1.Secret{}, enqueueOwner, builder.OnlyMetadata).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.requestsForAdmittedPod), builder.OnlyMetadata,
+			builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Watches(&apiextensionsv1.CustomResourceDefinition{}, handler.EnqueueRequestsFromMapFunc(r.requestsForSharedObject(
+			schema.GroupKind{Group: apiextensionsv1.GroupName, Kind: "CustomResourceDefinition"})), builder.OnlyMetadata).
+		Watches(&rbacv1.ClusterRole{}, enqueueOwner).
+		Watches(&rbacv1.ClusterRoleBinding{}, enqueueOwner).
+		Watches(&rbacv1.Role{}, enqueueOwner).
+		Watches(&rbacv1.RoleBinding{}, enqueueOwner).
+		Watches(&networkingv1.NetworkPolicy{}, enqueueOwner)
+
+	// Optional APIs are only watched if they are served when the operator starts
+	optional := []struct {
+		api       string
+		obj       client.Object
+		hasStatus bool
+	}{
+		{api: "OpenShift security", obj: &securityv1.SecurityContextConstraints{}},
+		{api: "CAPI", obj: &capiv1beta1.Cluster{}, hasStatus: true},
+		{api: "CAPI", obj: &capiv1beta1.MachineDeployment{}, hasStatus: true},
+		{api: "CAPOCI", obj: &infrastructurev1beta2.OCICluster{}, hasStatus: true},
+		{api: "CAPOCI", obj: &infrastructurev1beta2.OCIMachineTemplate{}},
+		{api: "CAPOCI", obj: &infrastructurev1beta2.OCIClusterIdentity{}},
+		{api: "Prometheus Operator", obj: monitoringObject(serviceMonitorGVK)},
+		{api: "Prometheus Operator", obj: monitoringObject(prometheusRuleGVK)},
+	}
+	for _, o := range optional {
+		installed, err := isKindInstalled(mgr, o.obj)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			mgr.GetLogger().Info("API not installed, not watching it", "api", o.api, "kind", fmt.Sprintf("%T", o.obj))
+			continue
+		}
+		if o.hasStatus {
+			b = b.Watches(o.obj, enqueueOwner, specChanged)
+		} else {
+			b = b.Watches(o.obj, enqueueOwner)
+		}
+	}
+
+	c, err := b.Build(r)
+	if err != nil {
+		return err
+	}
+
+	// The credential Secrets are not labeled by the operator, so the cache restricted by CacheOptions does
+	// not see them. Their metadata is watched in a cache of their own per namespace holding
+	// OCIClusterAutoscalers, started when the first OCIClusterAutoscaler of the namespace is reconciled.
+	r.secretWatches = &secretNamespaceWatches{watched: sets.New[string](), start: func(namespace string) error {
+		secrets, err := cache.New(mgr.GetConfig(), cache.Options{
+			HTTPClient:        mgr.GetHTTPClient(),
+			Scheme:            mgr.GetScheme(),
+			Mapper:            mgr.GetRESTMapper(),
+			DefaultNamespaces: map[string]cache.Config{namespace: {}},
+			DefaultTransform:  cache.TransformStripManagedFields(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := mgr.Add(secrets); err != nil {
+			return err
+		}
+		secret := &metav1.PartialObjectMetadata{}
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		return c.Watch(source.Kind[client.Object](secrets, secret, handler.EnqueueRequestsFromMapFunc(r.requestsForCredentialSecret)))
+	}}
+	return nil
 }
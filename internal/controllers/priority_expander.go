@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// priorityExpanderConfigMapName is the ConfigMap the cluster-autoscaler priority expander reads its priorities from
+	priorityExpanderConfigMapName = "cluster-autoscaler-priority-expander"
+)
+
+// hasNodeGroupPriorities returns true if at least one node group of the instance has a priority set
+func hasNodeGroupPriorities(instance *capiv1alpha1.OCIClusterAutoscaler) bool {
+	for _, group := range nodeGroups(instance) {
+		if group.priority != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// renderPriorityExpanderConfig renders the priorities of the node groups in the format expected by the
+// cluster-autoscaler priority expander. Node groups are matched by their clusterapi node group ID and
+// node groups without a priority are given priority 0 so that they remain eligible for scale-up.
+func renderPriorityExpanderConfig(instance *capiv1alpha1.OCIClusterAutoscaler) string {
+	priorities := map[int32][]string{}
+	for _, group := range nodeGroups(instance) {
+		var priority int32
+		if group.priority != nil {
+			priority = *group.priority
+		}
+		regex := fmt.Sprintf("^MachineDeployment/%s/%s$", regexp.QuoteMeta(group.namespace), regexp.QuoteMeta(group.name))
+		priorities[priority] = append(priorities[priority], regex)
+	}
+
+	keys := make([]int32, 0, len(priorities))
+	for priority := range priorities {
+		keys = append(keys, priority)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] > keys[j] })
+
+	var b strings.Builder
+	for _, priority := range keys {
+		fmt.Fprintf(&b, "%d:\n", priority)
+		for _, regex := range priorities[priority] {
+			fmt.Fprintf(&b, "  - '%s'\n", regex)
+		}
+	}
+	return b.String()
+}
+
+// reconcilePriorityExpanderConfig creates or updates the priority expander ConfigMap when node group
+// priorities are configured and removes it otherwise
+func (r *OCIClusterAutoscalerReconciler) reconcilePriorityExpanderConfig(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      priorityExpanderConfigMapName,
+			Namespace: capiSystemNamespace,
+		},
+	}
+
+	if !hasNodeGroupPriorities(autoscaler) {
+		if err := r.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete priority expander ConfigMap: %w", err)
+		}
+		return nil
+	}
+
+	setOwnerMetadata(configMap, autoscaler)
+	configMap.Data = map[string]string{
+		"priorities": renderPriorityExpanderConfig(autoscaler),
+	}
+	err := r.apply(ctx, configMap)
+	if err != nil {
+		return fmt.Errorf("failed to create/update priority expander ConfigMap: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/go-openapi/swag"
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("Priority expander configuration", func() {
+	var instance *capiv1alpha1.OCIClusterAutoscaler
+
+	BeforeEach(func() {
+		instance = &capiv1alpha1.OCIClusterAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers"},
+			Spec: capiv1alpha1.OCIClusterAutoscalerSpec{
+				Autoscaling: capiv1alpha1.AutoscalingConfig{
+					MaxNodes: 3,
+					Shape:    "VM.Standard.E4.Flex",
+				},
+			},
+		}
+	})
+
+	It("should not report priorities when none are set", func() {
+		Expect(hasNodeGroupPriorities(instance)).To(BeFalse())
+	})
+
+	It("should render node groups ordered by descending priority", func() {
+		instance.Spec.Autoscaling.Priority = swag.Int32(10)
+		instance.Spec.Autoscaling.NodeGroups = []capiv1alpha1.NodeGroupConfig{
+			{Name: "spot", MaxNodes: 5, Shape: "VM.Standard.E4.Flex", Preemptible: true, Priority: swag.Int32(50)},
+			{Name: "fallback", MaxNodes: 5, Shape: "VM.Standard3.Flex"},
+		}
+
+		Expect(hasNodeGroupPriorities(instance)).To(BeTrue())
+		Expect(renderPriorityExpanderConfig(instance)).To(Equal(
+			"50:\n" +
+				"  - '^MachineDeployment/capi-system/workers-spot$'\n" +
+				"10:\n" +
+				"  - '^MachineDeployment/capi-system/workers$'\n" +
+				"0:\n" +
+				"  - '^MachineDeployment/capi-system/workers-fallback$'\n"))
+	})
+
+	It("should group node groups sharing a priority", func() {
+		instance.Spec.Autoscaling.Priority = swag.Int32(1)
+		instance.Spec.Autoscaling.NodeGroups = []capiv1alpha1.NodeGroupConfig{
+			{Name: "a", MaxNodes: 1, Shape: "VM.Standard.E4.Flex", Priority: swag.Int32(1)},
+		}
+
+		Expect(renderPriorityExpanderConfig(instance)).To(Equal(
+			"1:\n" +
+				"  - '^MachineDeployment/capi-system/workers$'\n" +
+				"  - '^MachineDeployment/capi-system/workers-a$'\n"))
+	})
+
+	It("should create the MachineDeployments the priorities refer to when reconciling", func() {
+		autoscaler := newReconciledAutoscaler()
+		autoscaler.Spec.Autoscaling.Priority = swag.Int32(10)
+		autoscaler.Spec.Autoscaling.NodeGroups = []capiv1alpha1.NodeGroupConfig{
+			{Name: "spot", MaxNodes: 5, Shape: "VM.Standard.E4.Flex", Preemptible: true, Priority: swag.Int32(20)},
+		}
+		removed := &capiv1beta1.MachineDeployment{ObjectMeta: metav1.ObjectMeta{Name: "workers-removed", Namespace: capiSystemNamespace}}
+		setOwnerMetadata(removed, autoscaler)
+
+		applied, c := reconcileAutoscaler(autoscaler, removed)
+		for _, name := range []string{"workers", "workers-spot"} {
+			Expect(appliedObject(applied, "MachineDeployment", capiSystemNamespace, name)).NotTo(BeNil(), name)
+			Expect(appliedObject(applied, "OCIMachineTemplate", capiSystemNamespace, name+"-autoscaling")).NotTo(BeNil(), name)
+		}
+		config := appliedObject(applied, "ConfigMap", capiSystemNamespace, priorityExpanderConfigMapName)
+		Expect(config).NotTo(BeNil())
+		Expect(config.(*corev1.ConfigMap).Data).To(HaveKeyWithValue("priorities", ContainSubstring("^MachineDeployment/capi-system/workers-spot$")))
+
+		err := c.Get(ctx, client.ObjectKeyFromObject(removed), &capiv1beta1.MachineDeployment{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// ociCredentialsSecretName is the Secret holding the credentials CAPOCI authenticates with by default,
+	// written from the default OCIClusterAutoscaler
+	ociCredentialsSecretName = "oci-credentials"
+
+	// credentialsHashAnnotation records the hash of the credentials on the Secrets holding them and on the
+	// CAPOCI pod template, so that CAPOCI is rolled out whenever the credentials change. The pod template
+	// carries the hash of the default credentials under this key and the hash of the user principal of
+	// each cluster under a key suffixed with the cluster.
+	credentialsHashAnnotation = "capi.openshift.io/oci-credentials-hash"
+)
+
+// credentialsHash returns a hash of the content of the credentials
+func credentialsHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s=%d:", key, len(data[key]))
+		h.Write(data[key])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// clusterCredentialsSuffix identifies the cluster of the autoscaler in the pod template annotation and field
+// manager propagating its user principal to CAPOCI, within the length allowed for annotation names
+func clusterCredentialsSuffix(autoscaler *capiv1alpha1.OCIClusterAutoscaler) string {
+	sum := sha256.Sum256([]byte(capiNamespace(autoscaler) + "/" + clusterName(autoscaler)))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// reconcileOCICredentials writes the credentials of the cluster to its user principal Secret and, from the
+// default OCIClusterAutoscaler only, to the Secret CAPOCI authenticates with by default. CAPOCI is restarted
+// when either changes, and the rotation is reported in the status of the autoscaler whose credentials changed.
+func (r *OCIClusterAutoscalerReconciler) reconcileOCICredentials(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	data, err := r.ociCredentialsData(ctx, autoscaler)
+	if err != nil {
+		return err
+	}
+	if err := reportCredentials(autoscaler, data); err != nil {
+		return err
+	}
+	hash := credentialsHash(data)
+
+	var secrets []credentialsSecret
+	if userPrincipal(autoscaler) != nil {
+		suffix := clusterCredentialsSuffix(autoscaler)
+		secrets = append(secrets, credentialsSecret{
+			key:        r.principalSecretKey(autoscaler),
+			annotation: credentialsHashAnnotation + "-" + suffix,
+			manager:    credentialsFieldManager + "-" + suffix,
+		})
+	}
+	isDefault, err := r.isDefaultAutoscaler(ctx, autoscaler)
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		secrets = append(secrets, credentialsSecret{
+			key:        types.NamespacedName{Name: ociCredentialsSecretName, Namespace: capociNamespace},
+			annotation: credentialsHashAnnotation,
+			manager:    credentialsFieldManager,
+		})
+	}
+
+	rotated := false
+	for _, s := range secrets {
+		previousHash, err := r.appliedCredentialsHash(ctx, s.key)
+		if err != nil {
+			return err
+		}
+
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: s.key.Name, Namespace: s.key.Namespace}}
+		secret.Annotations = map[string]string{credentialsHashAnnotation: hash}
+		setOwnerMetadata(secret, autoscaler)
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = data
+		if err := r.apply(ctx, secret); err != nil {
+			return err
+		}
+
+		if err := r.restartCAPOCIOnCredentialsChange(ctx, s.annotation, s.manager, hash); err != nil {
+			return err
+		}
+		rotated = rotated || (previousHash != "" && previousHash != hash)
+	}
+
+	if rotated {
+		log.FromContext(ctx).Info("Propagated rotated OCI credentials to CAPOCI")
+		now := metav1.Now()
+		autoscaler.Status.CredentialsRotationTime = &now
+	}
+	return nil
+}
+
+// credentialsSecret is a Secret the credentials of an autoscaler are written to, with the pod template
+// annotation and the field manager restarting CAPOCI when they change
+type credentialsSecret struct {
+	key        types.NamespacedName
+	annotation string
+	manager    string
+}
+
+// isDefaultAutoscaler returns true when the autoscaler is the oldest OCIClusterAutoscaler not being deleted,
+// whose credentials CAPOCI uses by default
+func (r *OCIClusterAutoscalerReconciler) isDefaultAutoscaler(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) (bool, error) {
+	autoscalers := &capiv1alpha1.OCIClusterAutoscalerList{}
+	if err := r.List(ctx, autoscalers); err != nil {
+		return false, fmt.Errorf("failed to list OCIClusterAutoscalers: %w", err)
+	}
+
+	var oldest *capiv1alpha1.OCIClusterAutoscaler
+	for i := range autoscalers.Items {
+		candidate := &autoscalers.Items[i]
+		if !candidate.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if oldest == nil || candidate.CreationTimestamp.Before(&oldest.CreationTimestamp) ||
+			(candidate.CreationTimestamp.Equal(&oldest.CreationTimestamp) && client.ObjectKeyFromObject(candidate).String() < client.ObjectKeyFromObject(oldest).String()) {
+			oldest = candidate
+		}
+	}
+	return oldest != nil && oldest.UID == autoscaler.UID, nil
+}
+
+// appliedCredentialsHash returns the hash of the credentials currently in the Secret, or an empty string
+// when they were never applied
+func (r *OCIClusterAutoscalerReconciler) appliedCredentialsHash(ctx context.Context, key types.NamespacedName) (string, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, key, secret)
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get OCI credentials secret %s: %w", key, err)
+	}
+	return secret.Annotations[credentialsHashAnnotation], nil
+}
+
+// restartCAPOCIOnCredentialsChange sets the credentials hash under the annotation of the pod template of the
+// CAPOCI controller manager, which rolls it out when the credentials change. Nothing is done until CAPOCI is
+// installed.
+func (r *OCIClusterAutoscalerReconciler) restartCAPOCIOnCredentialsChange(ctx context.Context, annotation, manager, hash string) error {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: capociDeploymentName, Namespace: capociNamespace}, deployment)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get CAPOCI deployment: %w", err)
+	}
+	if deployment.Spec.Template.Annotations[annotation] == hash {
+		return nil
+	}
+
+	template := partialObject(appsv1.SchemeGroupVersion.WithKind("Deployment"), capociNamespace, capociDeploymentName)
+	if err := unstructured.SetNestedField(template.Object, hash, "spec", "template", "metadata", "annotations", annotation); err != nil {
+		return err
+	}
+	if err := applyAs(ctx, r.Client, template, manager); err != nil {
+		return fmt.Errorf("failed to restart CAPOCI with the new credentials: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("Credentials hash", func() {
+	data := func() map[string][]byte {
+		return map[string][]byte{
+			"tenancy":     []byte("ocid1.tenancy.oc1..aaaaaaaaexample"),
+			"user":        []byte("ocid1.user.oc1..aaaaaaaaexample"),
+			"fingerprint": []byte("aa:bb:cc:dd:ee:ff:00:11:22:33:44:55:66:77:88:99"),
+			"key":         []byte("key"),
+		}
+	}
+
+	It("should be stable for the same credentials", func() {
+		Expect(credentialsHash(data())).To(Equal(credentialsHash(data())))
+	})
+
+	It("should change when the key is rotated", func() {
+		rotated := data()
+		rotated["key"] = []byte("rotated key")
+		Expect(credentialsHash(rotated)).NotTo(Equal(credentialsHash(data())))
+	})
+
+	It("should not be fooled by values moving between keys", func() {
+		moved := data()
+		moved["key"] = []byte("")
+		moved["passphrase"] = []byte("key")
+		Expect(credentialsHash(moved)).NotTo(Equal(credentialsHash(data())))
+	})
+})
+
+var _ = Describe("Credentials propagation", func() {
+	var (
+		privateKey  []byte
+		fingerprint string
+	)
+
+	BeforeEach(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		privateKey = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		fingerprint, err = apiKeyFingerprint(privateKey, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	autoscaler := func(name string, created time.Time) *capiv1alpha1.OCIClusterAutoscaler {
+		a := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+		a.Spec.OCI = capiv1alpha1.OCIConfig{
+			TenancyID:           "ocid1.tenancy.oc1..aaaaaaaaexample",
+			Region:              "us-phoenix-1",
+			UserID:              "ocid1.user.oc1..aaaaaaaaexample",
+			Fingerprint:         fingerprint,
+			PrivateKeySecretRef: capiv1alpha1.SecretRef{Name: name + "-key"},
+		}
+		return a
+	}
+
+	keySecret := func(a *capiv1alpha1.OCIClusterAutoscaler) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: a.Spec.OCI.PrivateKeySecretRef.Name, Namespace: a.Namespace},
+			Data:       map[string][]byte{defaultPrivateKeySecretKey: privateKey},
+		}
+	}
+
+	capoci := func(annotations map[string]string) *appsv1.Deployment {
+		d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: capociDeploymentName, Namespace: capociNamespace}}
+		d.Spec.Template.Annotations = annotations
+		return d
+	}
+
+	hashSecret := func(key types.NamespacedName, hash string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        key.Name,
+			Namespace:   key.Namespace,
+			Annotations: map[string]string{credentialsHashAnnotation: hash},
+		}}
+	}
+
+	propagate := func(a *capiv1alpha1.OCIClusterAutoscaler, objs ...client.Object) []appliedPatch {
+		var applied []appliedPatch
+		c := newFakeClientBuilder().WithObjects(objs...).WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+		r := &OCIClusterAutoscalerReconciler{Client: c, APIReader: c, OperatorNamespace: operatorNamespace}
+		Expect(r.reconcileOCICredentials(ctx, a)).To(Succeed())
+		return applied
+	}
+
+	appliedNames := func(applied []appliedPatch) []string {
+		var names []string
+		for _, patch := range applied {
+			names = append(names, patch.obj.GetObjectKind().GroupVersionKind().Kind+"/"+patch.obj.GetName())
+		}
+		return names
+	}
+
+	templateAnnotations := func(applied []appliedPatch) map[string]string {
+		annotations := map[string]string{}
+		for _, patch := range applied {
+			if u, ok := patch.obj.(*unstructured.Unstructured); ok && u.GetKind() == "Deployment" {
+				values, _, _ := unstructured.NestedStringMap(u.Object, "spec", "template", "metadata", "annotations")
+				for key, value := range values {
+					annotations[key] = value
+				}
+				Expect(patch.options.FieldManager).To(HavePrefix(credentialsFieldManager))
+			}
+		}
+		return annotations
+	}
+
+	It("should only write the default credentials Secret from the oldest OCIClusterAutoscaler", func() {
+		oldest := autoscaler("oldest", time.Now().Add(-time.Hour))
+		newest := autoscaler("newest", time.Now())
+
+		applied := propagate(newest, oldest, newest, keySecret(newest))
+		Expect(appliedNames(applied)).To(ConsistOf("Secret/" + principalSecretName(newest)))
+
+		applied = propagate(oldest, oldest, newest, keySecret(oldest))
+		Expect(appliedNames(applied)).To(ConsistOf("Secret/"+principalSecretName(oldest), "Secret/"+ociCredentialsSecretName))
+	})
+
+	It("should restart CAPOCI under an annotation of the cluster when its user principal changes", func() {
+		a := autoscaler("workers", time.Now())
+		other := autoscaler("other", time.Now().Add(-time.Hour))
+		annotation := credentialsHashAnnotation + "-" + clusterCredentialsSuffix(a)
+		Expect(len(annotation) - len("capi.openshift.io/")).To(BeNumerically("<=", 63))
+
+		applied := propagate(a, a, other, keySecret(a), capoci(map[string]string{annotation: "old"}))
+		annotations := templateAnnotations(applied)
+		Expect(annotations).To(HaveKey(annotation))
+		Expect(annotations).NotTo(HaveKey(credentialsHashAnnotation))
+
+		hash := annotations[annotation]
+		applied = propagate(a, a, other, keySecret(a), capoci(map[string]string{annotation: hash}))
+		Expect(templateAnnotations(applied)).To(BeEmpty())
+
+		Expect(clusterCredentialsSuffix(other)).NotTo(Equal(clusterCredentialsSuffix(a)))
+	})
+
+	It("should only set the rotation time when previously applied credentials change", func() {
+		a := autoscaler("workers", time.Now())
+		principal := types.NamespacedName{Name: principalSecretName(a), Namespace: operatorNamespace}
+
+		propagate(a, a, keySecret(a))
+		Expect(a.Status.CredentialsRotationTime).To(BeNil())
+
+		applied := propagate(a, a, keySecret(a), hashSecret(principal, "previous"))
+		Expect(a.Status.CredentialsRotationTime).NotTo(BeNil())
+
+		a.Status.CredentialsRotationTime = nil
+		hash := applied[0].obj.GetAnnotations()[credentialsHashAnnotation]
+		shared := types.NamespacedName{Name: ociCredentialsSecretName, Namespace: capociNamespace}
+		propagate(a, a, keySecret(a), hashSecret(principal, hash), hashSecret(shared, hash))
+		Expect(a.Status.CredentialsRotationTime).To(BeNil())
+	})
+})
@@ -18,40 +18,232 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/go-openapi/swag"
 	securityv1 "github.com/openshift/api/security/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
 )
 
-// createSecurityContextConstraints creates the SCC for the OCIClusterAutoscaler
-// This is for the CAPI manager and CAPOCI controller manager
-func (r *OCIClusterAutoscalerReconciler) createSecurityContextConstraints(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
-	sccName := "oci-capi"
+const (
+	// sccName is the SCC the CAPI manager and CAPOCI controller manager run under
+	sccName = "oci-capi"
+
+	// sccUseRoleName follows the OpenShift naming of the ClusterRoles granting the use of an SCC
+	sccUseRoleName = "system:openshift:scc:" + sccName
+
+	// sccUseRoleBindingName is the RoleBinding granting the SCC to the ServiceAccounts of a namespace
+	sccUseRoleBindingName = "oci-capi-scc"
+
+	// sccAnnotation is set by OpenShift on a pod to the SCC it was admitted under
+	sccAnnotation = "openshift.io/scc"
+
+	// sccAdmittedCondition reports whether the pods of the CAPI controllers run under the oci-capi SCC
+	sccAdmittedCondition = "SCCAdmitted"
+
+	sccAdmittedReason    = "PodsAdmitted"
+	unexpectedSCCReason  = "UnexpectedSCC"
+	sccPodsMissingReason = "PodsNotFound"
+
+	// restrictedSCCName is the default OpenShift SCC the pods without a dedicated SCC are admitted under
+	restrictedSCCName = "restricted-v2"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+// sccWorkload is a Deployment whose pods must run under the oci-capi SCC
+type sccWorkload struct {
+	namespace      string
+	deployment     string
+	serviceAccount string
+}
+
+// sccWorkloads are the controllers the oci-capi SCC is granted to. Their pods run as the fixed non-root
+// UID of the upstream manifests, which is outside of the UID range restricted-v2 allows.
+var sccWorkloads = []sccWorkload{
+	{namespace: capiSystemNamespace, deployment: capiDeploymentName, serviceAccount: "capi-manager"},
+	{namespace: capociNamespace, deployment: capociDeploymentName, serviceAccount: capociDeploymentName},
+}
+
+// sccAdmission is a Deployment whose pods are checked to run under an SCC
+type sccAdmission struct {
+	namespace  string
+	deployment string
+	scc        string
+}
 
+// sccAdmissions returns the Deployments checked by the SCCAdmitted condition: the controllers granted the
+// oci-capi SCC, and the cluster-autoscaler. The cluster-autoscaler does not request a fixed UID, so it is
+// not granted the oci-capi SCC and must be admitted under restricted-v2 like any unprivileged pod.
+func sccAdmissions() []sccAdmission {
+	admissions := make([]sccAdmission, 0, len(sccWorkloads)+1)
+	for _, workload := range sccWorkloads {
+		admissions = append(admissions, sccAdmission{namespace: workload.namespace, deployment: workload.deployment, scc: sccName})
+	}
+	return append(admissions, sccAdmission{namespace: capiSystemNamespace, deployment: clusterAutoscalerName, scc: restrictedSCCName})
+}
+
+// securityContextConstraints returns the oci-capi SCC. It only admits what the CAPI controller pod specs
+// request: a non-root user, no capabilities, no privilege escalation, the runtime default seccomp profile
+// and Secret and projected service account token volumes.
+func securityContextConstraints() *securityv1.SecurityContextConstraints {
 	scc := &securityv1.SecurityContextConstraints{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: sccName,
 		},
 	}
+	scc.AllowPrivilegedContainer = false
+	scc.AllowPrivilegeEscalation = swag.Bool(false)
+	scc.DefaultAllowPrivilegeEscalation = swag.Bool(false)
+	scc.RequiredDropCapabilities = []corev1.Capability{"ALL"}
+	scc.AllowHostDirVolumePlugin = false
+	scc.AllowHostNetwork = false
+	scc.AllowHostPorts = false
+	scc.AllowHostPID = false
+	scc.AllowHostIPC = false
+	scc.ReadOnlyRootFilesystem = false
+	scc.Volumes = []securityv1.FSType{securityv1.FSTypeSecret, securityv1.FSProjected}
+	scc.RunAsUser = securityv1.RunAsUserStrategyOptions{
+		Type: securityv1.RunAsUserStrategyMustRunAsNonRoot,
+	}
+	scc.SELinuxContext = securityv1.SELinuxContextStrategyOptions{
+		Type: securityv1.SELinuxStrategyMustRunAs,
+	}
+	scc.FSGroup = securityv1.FSGroupStrategyOptions{
+		Type: securityv1.FSGroupStrategyMustRunAs,
+	}
+	scc.SupplementalGroups = securityv1.SupplementalGroupsStrategyOptions{
+		Type: securityv1.SupplementalGroupsStrategyRunAsAny,
+	}
+	scc.SeccompProfiles = []string{"runtime/default"}
+	return scc
+}
 
-	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, scc, func() error {
-		// Set the desired state of the SCC
-		scc.RunAsUser = securityv1.RunAsUserStrategyOptions{
-			Type: securityv1.RunAsUserStrategyRunAsAny,
+// createSecurityContextConstraints creates the SCC for the OCIClusterAutoscaler
+// This is for the CAPI manager and CAPOCI controller manager, which are granted its use through RBAC
+func (r *OCIClusterAutoscalerReconciler) createSecurityContextConstraints(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	scc := securityContextConstraints()
+	setOwnerMetadata(scc, autoscaler)
+	if err := r.apply(ctx, scc); err != nil {
+		return fmt.Errorf("failed to create/update SecurityContextConstraints: %w", err)
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: sccUseRoleName,
+		},
+	}
+	setOwnerMetadata(role, autoscaler)
+	role.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{securityv1.GroupName},
+			Resources:     []string{"securitycontextconstraints"},
+			ResourceNames: []string{sccName},
+			Verbs:         []string{"use"},
+		},
+	}
+	if err := r.apply(ctx, role); err != nil {
+		return fmt.Errorf("failed to create/update ClusterRole %s: %w", sccUseRoleName, err)
+	}
+
+	for _, workload := range sccWorkloads {
+		roleBinding := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sccUseRoleBindingName,
+				Namespace: workload.namespace,
+			},
+		}
+		setOwnerMetadata(roleBinding, autoscaler)
+		roleBinding.RoleRef = rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     sccUseRoleName,
 		}
-		scc.SELinuxContext = securityv1.SELinuxContextStrategyOptions{
-			Type: securityv1.SELinuxStrategyRunAsAny,
+		roleBinding.Subjects = []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      workload.serviceAccount,
+				Namespace: workload.namespace,
+			},
 		}
-		scc.SeccompProfiles = []string{"runtime/default"}
-		scc.Users = []string{
-			"system:serviceaccount:cluster-api-provider-oci-system:capoci-controller-manager",
-			"system:serviceaccount:capi-system:capi-manager",
+		if err := r.apply(ctx, roleBinding); err != nil {
+			return fmt.Errorf("failed to create/update RoleBinding %s/%s: %w", workload.namespace, sccUseRoleBindingName, err)
 		}
-		return nil
-	})
+	}
+	return nil
+}
 
-	return err
+// unexpectedSCCPods returns a description of the pods that were not admitted under the SCC
+func unexpectedSCCPods(pods []metav1.PartialObjectMetadata, scc string) []string {
+	var unexpected []string
+	for _, pod := range pods {
+		if admitted := pod.Annotations[sccAnnotation]; admitted != scc {
+			if admitted == "" {
+				admitted = "no SCC"
+			}
+			unexpected = append(unexpected, fmt.Sprintf("%s/%s runs under %s instead of %s", pod.Namespace, pod.Name, admitted, scc))
+		}
+	}
+	sort.Strings(unexpected)
+	return unexpected
+}
+
+// checkSCCAdmission sets the SCCAdmitted condition of the autoscaler from the SCC the pods of the CAPI
+// controllers and of the cluster-autoscaler were admitted under. Pods created before the SCC was granted
+// have to be deleted to be admitted under it. Deployments that are not installed are skipped.
+func (r *OCIClusterAutoscalerReconciler) checkSCCAdmission(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler) error {
+	var found bool
+	var unexpected []string
+	for _, admission := range sccAdmissions() {
+		deployment := &appsv1.Deployment{}
+		err := r.Get(ctx, types.NamespacedName{Name: admission.deployment, Namespace: admission.namespace}, deployment)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s/%s: %w", admission.namespace, admission.deployment, err)
+		}
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector of deployment %s/%s: %w", admission.namespace, admission.deployment, err)
+		}
+
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+		if err := r.List(ctx, list, client.InNamespace(admission.namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return fmt.Errorf("failed to list pods of deployment %s/%s: %w", admission.namespace, admission.deployment, err)
+		}
+		found = found || len(list.Items) > 0
+		unexpected = append(unexpected, unexpectedSCCPods(list.Items, admission.scc)...)
+	}
+	sort.Strings(unexpected)
+
+	condition := metav1.Condition{
+		Type:    sccAdmittedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  sccAdmittedReason,
+		Message: fmt.Sprintf("The CAPI controller pods run under the %s SCC and the cluster-autoscaler pods under %s", sccName, restrictedSCCName),
+	}
+	if !found {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = sccPodsMissingReason
+		condition.Message = "No CAPI controller or cluster-autoscaler pod is running"
+	} else if len(unexpected) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = unexpectedSCCReason
+		condition.Message = fmt.Sprintf("Pods must run under their expected SCC, delete them to recreate them: %s", strings.Join(unexpected, ", "))
+	}
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, condition)
+	return nil
 }
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	securityv1 "github.com/openshift/api/security/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("SecurityContextConstraints", func() {
+	It("should only admit unprivileged non-root pods", func() {
+		scc := securityContextConstraints()
+		Expect(scc.Name).To(Equal(sccName))
+		Expect(scc.AllowPrivilegedContainer).To(BeFalse())
+		Expect(*scc.AllowPrivilegeEscalation).To(BeFalse())
+		Expect(scc.RequiredDropCapabilities).To(ConsistOf(corev1.Capability("ALL")))
+		Expect(scc.AllowedCapabilities).To(BeEmpty())
+		Expect(scc.AllowHostNetwork || scc.AllowHostPorts || scc.AllowHostPID || scc.AllowHostIPC || scc.AllowHostDirVolumePlugin).To(BeFalse())
+		Expect(scc.Volumes).To(ConsistOf(securityv1.FSTypeSecret, securityv1.FSProjected))
+		Expect(scc.RunAsUser.Type).To(Equal(securityv1.RunAsUserStrategyMustRunAsNonRoot))
+		Expect(scc.Users).To(BeEmpty())
+		Expect(scc.Groups).To(BeEmpty())
+	})
+
+	It("should report the pods not admitted under the SCC", func() {
+		pod := func(name, scc string) metav1.PartialObjectMetadata {
+			p := metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: capiSystemNamespace}}
+			if scc != "" {
+				p.Annotations = map[string]string{sccAnnotation: scc}
+			}
+			return p
+		}
+
+		Expect(unexpectedSCCPods([]metav1.PartialObjectMetadata{pod("admitted", sccName)}, sccName)).To(BeEmpty())
+		Expect(unexpectedSCCPods([]metav1.PartialObjectMetadata{
+			pod("admitted", sccName),
+			pod("restricted", "restricted-v2"),
+			pod("unannotated", ""),
+		}, sccName)).To(Equal([]string{
+			capiSystemNamespace + "/restricted runs under restricted-v2 instead of oci-capi",
+			capiSystemNamespace + "/unannotated runs under no SCC instead of oci-capi",
+		}))
+	})
+
+	It("should expect the cluster-autoscaler pods to run under restricted-v2", func() {
+		deployment := func(namespace, name string) *appsv1.Deployment {
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+			}
+		}
+		pod := func(namespace, name, app, scc string) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Labels:      map[string]string{"app": app},
+				Annotations: map[string]string{sccAnnotation: scc},
+			}}
+		}
+
+		c := newFakeClientBuilder().WithObjects(
+			deployment(capociNamespace, capociDeploymentName),
+			deployment(capiSystemNamespace, clusterAutoscalerName),
+			pod(capociNamespace, "capoci", capociDeploymentName, sccName),
+			pod(capiSystemNamespace, "autoscaler", clusterAutoscalerName, restrictedSCCName),
+		).Build()
+		r := &OCIClusterAutoscalerReconciler{Client: c}
+
+		autoscaler := &capiv1alpha1.OCIClusterAutoscaler{}
+		Expect(r.checkSCCAdmission(ctx, autoscaler)).To(Succeed())
+		condition := meta.FindStatusCondition(autoscaler.Status.Conditions, sccAdmittedCondition)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		Expect(c.Create(ctx, pod(capiSystemNamespace, "privileged", clusterAutoscalerName, sccName))).To(Succeed())
+		Expect(r.checkSCCAdmission(ctx, autoscaler)).To(Succeed())
+		condition = meta.FindStatusCondition(autoscaler.Status.Conditions, sccAdmittedCondition)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(unexpectedSCCReason))
+		Expect(condition.Message).To(HaveSuffix(capiSystemNamespace + "/privileged runs under oci-capi instead of restricted-v2"))
+	})
+})
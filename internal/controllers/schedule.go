@@ -0,0 +1,349 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// scheduleOverrideName is reported as the active schedule when the schedule override is applied
+	scheduleOverrideName = "Override"
+
+	// maxScheduleDuration bounds the length of a schedule time window
+	maxScheduleDuration = 7 * 24 * time.Hour
+
+	minSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size"
+	maxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
+)
+
+// cronSchedule is a parsed five field cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day fields start with *. When both are restricted a
+	// day matches if either of them matches, as in cron(8).
+	domStar, dowStar bool
+}
+
+// parseCron parses a five field cron expression supporting *, lists, ranges and steps
+func parseCron(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in cron expression %q, found %d", spec, len(fields))
+	}
+
+	var err error
+	s := &cronSchedule{
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+	// Sunday can be written as 0 or 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps into a bit set
+func parseCronField(field string, lowest, highest int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart = part[:i]
+		}
+
+		start, end := lowest, highest
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			start, err1 = strconv.Atoi(bounds[0])
+			end, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			start, end = value, value
+			if step > 1 {
+				end = highest
+			}
+		}
+		if start < lowest || end > highest || start > end {
+			return 0, fmt.Errorf("%q is out of range [%d-%d]", part, lowest, highest)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// matches returns true if the schedule fires at the minute of t
+func (s *cronSchedule) matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 &&
+		s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 &&
+		s.dayMatches(t)
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time after t at which the schedule fires, or the zero time if it
+// does not fire within the next five years
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// lastStart returns the latest time in (t-lookback, t] at which the schedule fires
+func (s *cronSchedule) lastStart(t time.Time, lookback time.Duration) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	for elapsed := time.Duration(0); elapsed < lookback; elapsed += time.Minute {
+		candidate := t.Add(-elapsed)
+		if s.matches(candidate) {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nodeGroupCapacity is the size limits of a node group at a point in time
+type nodeGroupCapacity struct {
+	minNodes int32
+	maxNodes int32
+	// active is the name of the applied schedule, scheduleOverrideName or empty
+	active string
+	// activeUntil is the end of the applied time window or override
+	activeUntil *time.Time
+	// nextTransition is the earliest time at which the capacity may change
+	nextTransition *time.Time
+}
+
+// capacityAt returns the size limits of the node group at now, taking its schedules and override into account
+func (g nodeGroup) capacityAt(now time.Time) (nodeGroupCapacity, error) {
+	capacity := nodeGroupCapacity{minNodes: g.minNodes, maxNodes: g.maxNodes}
+	observe := func(t time.Time) {
+		if capacity.nextTransition == nil || t.Before(*capacity.nextTransition) {
+			capacity.nextTransition = &t
+		}
+	}
+
+	var activeStart time.Time
+	var activeSchedule *capiv1alpha1.CapacitySchedule
+	for i := range g.schedules {
+		schedule := &g.schedules[i]
+		cron, loc, err := parseCapacitySchedule(schedule)
+		if err != nil {
+			return capacity, err
+		}
+
+		localNow := now.In(loc)
+		if next := cron.next(localNow); !next.IsZero() {
+			observe(next)
+		}
+
+		start, ok := cron.lastStart(localNow, schedule.Duration.Duration)
+		if !ok {
+			continue
+		}
+		// The window that started last wins, ties go to the schedule listed first
+		if activeSchedule == nil || start.After(activeStart) {
+			activeStart, activeSchedule = start, schedule
+		}
+	}
+
+	if activeSchedule != nil {
+		end := activeStart.Add(activeSchedule.Duration.Duration)
+		observe(end)
+		capacity.active, capacity.activeUntil = activeSchedule.Name, &end
+		capacity.minNodes, capacity.maxNodes = limitsOrDefault(activeSchedule.MinNodes, activeSchedule.MaxNodes, g.minNodes, g.maxNodes)
+	}
+
+	if override := g.scheduleOverride; override != nil {
+		if override.ExpiresAt == nil || now.Before(override.ExpiresAt.Time) {
+			capacity.active, capacity.activeUntil = scheduleOverrideName, nil
+			if override.ExpiresAt != nil {
+				expiresAt := override.ExpiresAt.Time
+				capacity.activeUntil = &expiresAt
+				observe(expiresAt)
+			}
+			capacity.minNodes, capacity.maxNodes = limitsOrDefault(override.MinNodes, override.MaxNodes, g.minNodes, g.maxNodes)
+		}
+	}
+
+	if capacity.minNodes > capacity.maxNodes {
+		return capacity, fmt.Errorf("node group %s: minNodes [%d] of %q must be less than or equal to maxNodes [%d]",
+			g.name, capacity.minNodes, capacity.active, capacity.maxNodes)
+	}
+	return capacity, nil
+}
+
+func limitsOrDefault(minNodes, maxNodes *int32, defaultMin, defaultMax int32) (int32, int32) {
+	if minNodes != nil {
+		defaultMin = *minNodes
+	}
+	if maxNodes != nil {
+		defaultMax = *maxNodes
+	}
+	return defaultMin, defaultMax
+}
+
+// parseCapacitySchedule parses the cron expression and time zone of a schedule
+func parseCapacitySchedule(schedule *capiv1alpha1.CapacitySchedule) (*cronSchedule, *time.Location, error) {
+	cron, err := parseCron(schedule.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schedule %s: %w", schedule.Name, err)
+	}
+	loc := time.UTC
+	if schedule.TimeZone != "" {
+		if loc, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("schedule %s: invalid time zone %q: %w", schedule.Name, schedule.TimeZone, err)
+		}
+	}
+	if schedule.Duration.Duration <= 0 || schedule.Duration.Duration > maxScheduleDuration {
+		return nil, nil, fmt.Errorf("schedule %s: duration must be greater than 0 and at most %s", schedule.Name, maxScheduleDuration)
+	}
+	return cron, loc, nil
+}
+
+// reconcileCapacitySchedules applies the size limits of the node groups at now to their existing
+// MachineDeployments, reports them in status and returns how long to wait for the next transition
+func (r *OCIClusterAutoscalerReconciler) reconcileCapacitySchedules(ctx context.Context, autoscaler *capiv1alpha1.OCIClusterAutoscaler, now time.Time) (time.Duration, error) {
+	var requeueAfter time.Duration
+	statuses := make([]capiv1alpha1.NodeGroupStatus, 0, len(autoscaler.Spec.Autoscaling.NodeGroups)+1)
+
+	for _, group := range nodeGroups(autoscaler) {
+		capacity, err := group.capacityAt(now)
+		if err != nil {
+			return 0, err
+		}
+
+		status := capiv1alpha1.NodeGroupStatus{
+			Name:           group.name,
+			MinNodes:       capacity.minNodes,
+			MaxNodes:       capacity.maxNodes,
+			ActiveSchedule: capacity.active,
+		}
+		if capacity.activeUntil != nil {
+			status.ActiveUntil = &metav1.Time{Time: *capacity.activeUntil}
+		}
+		statuses = append(statuses, status)
+
+		if capacity.nextTransition != nil {
+			if wait := capacity.nextTransition.Sub(now); requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+		}
+
+		if err := r.applyNodeGroupCapacity(ctx, group, capacity); err != nil {
+			return 0, err
+		}
+	}
+
+	autoscaler.Status.NodeGroups = statuses
+	return requeueAfter, nil
+}
+
+// applyNodeGroupCapacity applies the size annotations of the MachineDeployment of the node group if it
+// exists. The annotations are owned by a dedicated field manager so that schedule transitions do not
+// require re-applying the rest of the MachineDeployment.
+func (r *OCIClusterAutoscalerReconciler) applyNodeGroupCapacity(ctx context.Context, group nodeGroup, capacity nodeGroupCapacity) error {
+	machineDeployment := &capiv1beta1.MachineDeployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: group.name, Namespace: group.namespace}, machineDeployment)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get MachineDeployment %s: %w", group.name, err)
+	}
+
+	sizes := partialObject(capiv1beta1.GroupVersion.WithKind("MachineDeployment"), group.namespace, group.name)
+	sizes.SetAnnotations(map[string]string{
+		minSizeAnnotation: fmt.Sprintf("%d", capacity.minNodes),
+		maxSizeAnnotation: fmt.Sprintf("%d", capacity.maxNodes),
+	})
+	if err := applyAs(ctx, r.Client, sizes, capacityFieldManager); err != nil {
+		return fmt.Errorf("failed to update size of MachineDeployment %s: %w", group.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/go-openapi/swag"
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("Capacity schedules", func() {
+	Context("When parsing cron expressions", func() {
+		It("should reject malformed expressions", func() {
+			for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+				_, err := parseCron(spec)
+				Expect(err).To(HaveOccurred(), spec)
+			}
+		})
+
+		It("should find the next activation", func() {
+			cron, err := parseCron("0 8 * * 1-5")
+			Expect(err).NotTo(HaveOccurred())
+
+			// Saturday
+			from := time.Date(2025, time.March, 8, 12, 0, 0, 0, time.UTC)
+			Expect(cron.next(from)).To(Equal(time.Date(2025, time.March, 10, 8, 0, 0, 0, time.UTC)))
+		})
+
+		It("should treat Sunday as 0 or 7", func() {
+			cron, err := parseCron("30 6 * * 7")
+			Expect(err).NotTo(HaveOccurred())
+
+			sunday := time.Date(2025, time.March, 9, 6, 30, 0, 0, time.UTC)
+			Expect(cron.matches(sunday)).To(BeTrue())
+		})
+
+		It("should match either day field when both are restricted", func() {
+			cron, err := parseCron("0 0 1 * 1")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cron.matches(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))).To(BeTrue())  // 1st, Saturday
+			Expect(cron.matches(time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC))).To(BeTrue())  // Monday
+			Expect(cron.matches(time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC))).To(BeFalse()) // Tuesday
+		})
+	})
+
+	Context("When computing node group capacity", func() {
+		var group nodeGroup
+
+		BeforeEach(func() {
+			group = nodeGroup{
+				name:     "workers",
+				minNodes: 1,
+				maxNodes: 3,
+				schedules: []capiv1alpha1.CapacitySchedule{
+					{
+						Name:     "business-hours",
+						Schedule: "0 8 * * 1-5",
+						TimeZone: "Europe/Berlin",
+						Duration: metav1.Duration{Duration: 10 * time.Hour},
+						MinNodes: swag.Int32(3),
+						MaxNodes: swag.Int32(10),
+					},
+				},
+			}
+		})
+
+		It("should apply the node group limits outside of the window", func() {
+			now := time.Date(2025, time.March, 10, 6, 0, 0, 0, time.UTC) // 07:00 in Berlin
+			capacity, err := group.capacityAt(now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capacity.minNodes).To(Equal(int32(1)))
+			Expect(capacity.maxNodes).To(Equal(int32(3)))
+			Expect(capacity.active).To(BeEmpty())
+			Expect(*capacity.nextTransition).To(BeTemporally("==", time.Date(2025, time.March, 10, 7, 0, 0, 0, time.UTC)))
+		})
+
+		It("should apply the schedule limits inside of the window in its time zone", func() {
+			now := time.Date(2025, time.March, 10, 7, 30, 0, 0, time.UTC) // 08:30 in Berlin
+			capacity, err := group.capacityAt(now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capacity.minNodes).To(Equal(int32(3)))
+			Expect(capacity.maxNodes).To(Equal(int32(10)))
+			Expect(capacity.active).To(Equal("business-hours"))
+			Expect(*capacity.activeUntil).To(BeTemporally("==", time.Date(2025, time.March, 10, 17, 0, 0, 0, time.UTC)))
+		})
+
+		It("should apply the window that started last when windows overlap", func() {
+			group.schedules = append(group.schedules, capiv1alpha1.CapacitySchedule{
+				Name:     "month-end",
+				Schedule: "0 12 10 * *",
+				TimeZone: "Europe/Berlin",
+				Duration: metav1.Duration{Duration: 2 * time.Hour},
+				MaxNodes: swag.Int32(20),
+			})
+
+			now := time.Date(2025, time.March, 10, 11, 30, 0, 0, time.UTC) // 12:30 in Berlin
+			capacity, err := group.capacityAt(now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capacity.active).To(Equal("month-end"))
+			Expect(capacity.minNodes).To(Equal(int32(1)))
+			Expect(capacity.maxNodes).To(Equal(int32(20)))
+		})
+
+		It("should prefer the override over the schedules until it expires", func() {
+			expiresAt := metav1.NewTime(time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC))
+			group.scheduleOverride = &capiv1alpha1.CapacityOverride{
+				MinNodes:  swag.Int32(0),
+				MaxNodes:  swag.Int32(0),
+				ExpiresAt: &expiresAt,
+			}
+
+			capacity, err := group.capacityAt(time.Date(2025, time.March, 10, 8, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capacity.active).To(Equal(scheduleOverrideName))
+			Expect(capacity.maxNodes).To(Equal(int32(0)))
+
+			capacity, err = group.capacityAt(time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(capacity.active).To(Equal("business-hours"))
+		})
+
+		It("should reject limits with a minimum above the maximum", func() {
+			group.schedules[0].MinNodes = swag.Int32(5)
+			group.schedules[0].MaxNodes = nil
+			_, err := group.capacityAt(time.Date(2025, time.March, 10, 8, 0, 0, 0, time.UTC))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
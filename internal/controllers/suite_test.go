@@ -26,9 +26,16 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	securityv1 "github.com/openshift/api/security/v1"
+	infrastructurev1beta2 "github.com/oracle/cluster-api-provider-oci/api/v1beta2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -46,6 +53,19 @@ var testEnv *envtest.Environment
 var ctx context.Context
 var cancel context.CancelFunc
 
+// newFakeClientBuilder returns a fake client builder knowing the types the controllers read and write. The
+// fake client does not support server-side apply, tests of reconcilers applying objects intercept the patches.
+func newFakeClientBuilder() *fake.ClientBuilder {
+	s := k8sruntime.NewScheme()
+	utilruntime.Must(scheme.AddToScheme(s))
+	utilruntime.Must(apiextensionsv1.AddToScheme(s))
+	utilruntime.Must(securityv1.AddToScheme(s))
+	utilruntime.Must(capiv1beta1.AddToScheme(s))
+	utilruntime.Must(infrastructurev1beta2.AddToScheme(s))
+	utilruntime.Must(capiv1alpha1.AddToScheme(s))
+	return fake.NewClientBuilder().WithScheme(s)
+}
+
 func TestControllers(t *testing.T) {
 	RegisterFailHandler(Fail)
 
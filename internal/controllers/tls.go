@@ -0,0 +1,277 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// apiServerConfigName is the cluster APIServer configuration holding the TLS security profile
+	apiServerConfigName = "cluster"
+
+	// tlsFieldManager owns the TLS arguments of the CAPI and CAPOCI controller managers
+	tlsFieldManager = "oci-capi-operator-tls"
+
+	// managerContainer is the container of the CAPI and CAPOCI controller manager Deployments
+	managerContainer = "manager"
+
+	tlsMinVersionFlag   = "--tls-min-version"
+	tlsCipherSuitesFlag = "--tls-cipher-suites"
+)
+
+// +kubebuilder:rbac:groups=config.openshift.io,resources=apiservers,verbs=get;list;watch
+
+// tlsVersions maps the TLS protocol versions of the security profiles to their Go values
+var tlsVersions = map[configv1.TLSProtocolVersion]uint16{
+	configv1.VersionTLS10: tls.VersionTLS10,
+	configv1.VersionTLS11: tls.VersionTLS11,
+	configv1.VersionTLS12: tls.VersionTLS12,
+	configv1.VersionTLS13: tls.VersionTLS13,
+}
+
+// openSSLCipherSuites maps the OpenSSL names of the ciphers of the security profiles to their IANA names.
+// The DHE ciphers have no Go implementation.
+var openSSLCipherSuites = map[string]string{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-RSA-AES128-GCM-SHA256":   "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	"ECDHE-ECDSA-AES256-GCM-SHA384": "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-RSA-AES256-GCM-SHA384":   "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	"ECDHE-ECDSA-CHACHA20-POLY1305": "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-RSA-CHACHA20-POLY1305":   "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	"ECDHE-ECDSA-AES128-SHA256":     "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-RSA-AES128-SHA256":       "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	"ECDHE-ECDSA-AES128-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-RSA-AES128-SHA":          "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	"ECDHE-ECDSA-AES256-SHA":        "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	"ECDHE-RSA-AES256-SHA":          "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	"AES128-GCM-SHA256":             "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	"AES256-GCM-SHA384":             "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	"AES128-SHA256":                 "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	"AES128-SHA":                    "TLS_RSA_WITH_AES_128_CBC_SHA",
+	"AES256-SHA":                    "TLS_RSA_WITH_AES_256_CBC_SHA",
+	"DES-CBC3-SHA":                  "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+}
+
+// tlsProfileSpec returns the TLS settings of the security profile. Clusters without a profile use the
+// Intermediate profile.
+func tlsProfileSpec(profile *configv1.TLSSecurityProfile) *configv1.TLSProfileSpec {
+	if profile == nil || profile.Type == "" {
+		return configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	}
+	if profile.Type == configv1.TLSProfileCustomType {
+		if profile.Custom == nil {
+			return configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+		}
+		return &profile.Custom.TLSProfileSpec
+	}
+	if spec, ok := configv1.TLSProfiles[profile.Type]; ok {
+		return spec
+	}
+	return configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+}
+
+// FetchTLSProfile returns the TLS settings of the security profile of the cluster APIServer configuration,
+// or nil when the cluster is not an OpenShift cluster
+func FetchTLSProfile(ctx context.Context, c client.Reader) (*configv1.TLSProfileSpec, error) {
+	apiServer := &configv1.APIServer{}
+	err := c.Get(ctx, types.NamespacedName{Name: apiServerConfigName}, apiServer)
+	if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get APIServer configuration: %w", err)
+	}
+	return tlsProfileSpec(apiServer.Spec.TLSSecurityProfile), nil
+}
+
+// cipherSuites returns the IANA names of the ciphers of the profile that are configurable in Go, in the
+// order of the profile. TLS 1.3 ciphers are not configurable and unknown ciphers are left out.
+func cipherSuites(spec *configv1.TLSProfileSpec) []string {
+	var names []string
+	for _, cipher := range spec.Ciphers {
+		if name, ok := openSSLCipherSuites[cipher]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// cipherSuiteIDs returns the Go identifiers of the ciphers of the profile
+func cipherSuiteIDs(spec *configv1.TLSProfileSpec) []uint16 {
+	ids := map[string]uint16{}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		ids[suite.Name] = suite.ID
+	}
+	var suites []uint16
+	for _, name := range cipherSuites(spec) {
+		if id, ok := ids[name]; ok {
+			suites = append(suites, id)
+		}
+	}
+	return suites
+}
+
+// TLSConfig returns an option applying the minimum version and ciphers of the TLS profile to a server
+func TLSConfig(spec *configv1.TLSProfileSpec) func(*tls.Config) {
+	return func(c *tls.Config) {
+		if version, ok := tlsVersions[spec.MinTLSVersion]; ok {
+			c.MinVersion = version
+		}
+		if suites := cipherSuiteIDs(spec); len(suites) > 0 {
+			c.CipherSuites = suites
+		}
+	}
+}
+
+// withTLSArgs replaces the TLS arguments of a controller-runtime based controller with those of the TLS
+// profile. The arguments are left unchanged when there is no profile.
+func withTLSArgs(args []string, spec *configv1.TLSProfileSpec) []string {
+	if spec == nil {
+		return args
+	}
+	result := slices.DeleteFunc(slices.Clone(args), func(arg string) bool {
+		return strings.HasPrefix(arg, tlsMinVersionFlag+"=") || strings.HasPrefix(arg, tlsCipherSuitesFlag+"=")
+	})
+	result = append(result, tlsMinVersionFlag+"="+string(spec.MinTLSVersion))
+	if suites := cipherSuites(spec); len(suites) > 0 && spec.MinTLSVersion != configv1.VersionTLS13 {
+		result = append(result, tlsCipherSuitesFlag+"="+strings.Join(suites, ","))
+	}
+	return result
+}
+
+// tlsProfileDeployments are the controller managers installed by clusterctl that the TLS profile is applied to
+var tlsProfileDeployments = []types.NamespacedName{
+	{Namespace: capiSystemNamespace, Name: capiDeploymentName},
+	{Namespace: capociNamespace, Name: capociDeploymentName},
+}
+
+// applyTLSProfile sets the TLS arguments of the CAPI and CAPOCI controller managers from the TLS profile of
+// the cluster. Controller managers that are not installed yet are skipped.
+func (r *OCIClusterAutoscalerReconciler) applyTLSProfile(ctx context.Context) error {
+	if r.TLSProfile == nil {
+		return nil
+	}
+	for _, key := range tlsProfileDeployments {
+		if err := r.applyManagerTLSArgs(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyManagerTLSArgs sets the TLS arguments of the manager container of a controller manager Deployment.
+// The args list is atomic, so the applied patch owns every argument of the manager container, not only the
+// TLS ones.
+func (r *OCIClusterAutoscalerReconciler) applyManagerTLSArgs(ctx context.Context, key types.NamespacedName) error {
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, key, deployment)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get deployment %s: %w", key, err)
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != managerContainer {
+			continue
+		}
+		args := withTLSArgs(container.Args, r.TLSProfile)
+		if slices.Equal(args, container.Args) {
+			return nil
+		}
+
+		manager := partialObject(appsv1.SchemeGroupVersion.WithKind("Deployment"), key.Namespace, key.Name)
+		containers := []interface{}{
+			map[string]interface{}{"name": managerContainer, "args": stringsToInterfaces(args)},
+		}
+		if err := unstructured.SetNestedSlice(manager.Object, containers, "spec", "template", "spec", "containers"); err != nil {
+			return err
+		}
+		if err := applyAs(ctx, r.Client, manager, tlsFieldManager); err != nil {
+			return fmt.Errorf("failed to apply the TLS profile to %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// stringsToInterfaces converts strings to the values of an unstructured slice
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// TLSProfileWatcher calls OnChange when the TLS profile of the cluster no longer matches the profile the
+// operator servers were started with, so that the operator restarts with the new profile
+type TLSProfileWatcher struct {
+	client.Client
+
+	// Profile is the TLS profile the servers were started with, nil when the cluster had no APIServer
+	// configuration
+	Profile *configv1.TLSProfileSpec
+
+	// OnChange is called when the TLS profile of the cluster changes
+	OnChange func()
+}
+
+// Reconcile compares the TLS profile of the APIServer configuration with the profile in use
+func (r *TLSProfileWatcher) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	profile, err := FetchTLSProfile(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !equality.Semantic.DeepEqual(profile, r.Profile) {
+		log.FromContext(ctx).Info("TLS security profile changed, restarting to apply it")
+		r.OnChange()
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the watcher with the Manager. Clusters without the OpenShift config API are not watched.
+func (r *TLSProfileWatcher) SetupWithManager(mgr ctrl.Manager) error {
+	installed, err := isKindInstalled(mgr, &configv1.APIServer{})
+	if err != nil || !installed {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tlsprofile").
+		For(&configv1.APIServer{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == apiServerConfigName
+		}))).
+		Complete(r)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("TLS security profile", func() {
+	intermediate := configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+
+	It("should default to the Intermediate profile", func() {
+		Expect(tlsProfileSpec(nil)).To(Equal(intermediate))
+		Expect(tlsProfileSpec(&configv1.TLSSecurityProfile{})).To(Equal(intermediate))
+		Expect(tlsProfileSpec(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileCustomType})).To(Equal(intermediate))
+	})
+
+	It("should use the settings of the profile type", func() {
+		Expect(tlsProfileSpec(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType})).
+			To(Equal(configv1.TLSProfiles[configv1.TLSProfileModernType]))
+
+		custom := &configv1.TLSSecurityProfile{
+			Type: configv1.TLSProfileCustomType,
+			Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+				Ciphers:       []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+				MinTLSVersion: configv1.VersionTLS12,
+			}},
+		}
+		Expect(tlsProfileSpec(custom)).To(Equal(&custom.Custom.TLSProfileSpec))
+	})
+
+	It("should translate the OpenSSL cipher names and leave out the TLS 1.3 and DHE ciphers", func() {
+		suites := cipherSuites(intermediate)
+		Expect(suites).To(HaveExactElements(
+			"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+			"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+		))
+		Expect(cipherSuites(configv1.TLSProfiles[configv1.TLSProfileModernType])).To(BeEmpty())
+	})
+
+	It("should configure the servers with the minimum version and ciphers of the profile", func() {
+		config := &tls.Config{}
+		TLSConfig(intermediate)(config)
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(config.CipherSuites).To(HaveLen(6))
+		Expect(config.CipherSuites).To(ContainElement(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256))
+
+		config = &tls.Config{}
+		TLSConfig(configv1.TLSProfiles[configv1.TLSProfileModernType])(config)
+		Expect(config.MinVersion).To(Equal(uint16(tls.VersionTLS13)))
+		Expect(config.CipherSuites).To(BeNil())
+	})
+
+	It("should replace the TLS arguments of the controllers", func() {
+		args := []string{"--leader-elect", "--tls-min-version=VersionTLS10", "--tls-cipher-suites=TLS_RSA_WITH_AES_128_CBC_SHA"}
+		Expect(withTLSArgs(args, nil)).To(Equal(args))
+
+		updated := withTLSArgs(args, intermediate)
+		Expect(updated).To(HaveExactElements(
+			"--leader-elect",
+			"--tls-min-version=VersionTLS12",
+			HavePrefix("--tls-cipher-suites=TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,"),
+		))
+		Expect(args[1]).To(Equal("--tls-min-version=VersionTLS10"))
+
+		Expect(withTLSArgs(args, configv1.TLSProfiles[configv1.TLSProfileModernType])).
+			To(HaveExactElements("--leader-elect", "--tls-min-version=VersionTLS13"))
+	})
+
+	Describe("controller managers", func() {
+		manager := func(namespace, name string) *appsv1.Deployment {
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name:  managerContainer,
+					Image: name,
+					Args:  []string{"--leader-elect", "--tls-min-version=VersionTLS10"},
+				}}}}},
+			}
+		}
+
+		It("should leave the controller managers alone without a profile", func() {
+			var applied []appliedPatch
+			c := newFakeClientBuilder().WithObjects(manager(capociNamespace, capociDeploymentName)).
+				WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+			r := &OCIClusterAutoscalerReconciler{Client: c}
+
+			Expect(r.applyTLSProfile(ctx)).To(Succeed())
+			Expect(applied).To(BeEmpty())
+		})
+
+		It("should only apply the args of the manager containers of CAPI and CAPOCI", func() {
+			var applied []appliedPatch
+			c := newFakeClientBuilder().
+				WithObjects(manager(capiSystemNamespace, capiDeploymentName), manager(capociNamespace, capociDeploymentName)).
+				WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+			r := &OCIClusterAutoscalerReconciler{Client: c, TLSProfile: configv1.TLSProfiles[configv1.TLSProfileModernType]}
+
+			Expect(r.applyTLSProfile(ctx)).To(Succeed())
+			Expect(applied).To(HaveLen(2))
+			for i, key := range tlsProfileDeployments {
+				Expect(applied[i].options.FieldManager).To(Equal(tlsFieldManager))
+				data, err := json.Marshal(applied[i].obj)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(MatchJSON(fmt.Sprintf(`{
+					"apiVersion": "apps/v1",
+					"kind": "Deployment",
+					"metadata": {"name": %q, "namespace": %q},
+					"spec": {"template": {"spec": {"containers": [
+						{"name": "manager", "args": ["--leader-elect", "--tls-min-version=VersionTLS13"]}
+					]}}}
+				}`, key.Name, key.Namespace)))
+			}
+		})
+
+		It("should skip the controller managers that are not installed", func() {
+			var applied []appliedPatch
+			c := newFakeClientBuilder().WithObjects(manager(capociNamespace, capociDeploymentName)).
+				WithInterceptorFuncs(recordApplyPatches(&applied)).Build()
+			r := &OCIClusterAutoscalerReconciler{Client: c, TLSProfile: configv1.TLSProfiles[configv1.TLSProfileModernType]}
+
+			Expect(r.applyTLSProfile(ctx)).To(Succeed())
+			Expect(applied).To(HaveLen(1))
+			Expect(applied[0].obj.GetName()).To(Equal(capociDeploymentName))
+		})
+	})
+
+	It("should serve the cluster-autoscaler metrics through a proxy with the profile", func() {
+		proxy := clusterAutoscalerMetricsProxy(configv1.TLSProfiles[configv1.TLSProfileModernType])
+		Expect(proxy.Args).To(ContainElements(
+			"--secure-listen-address=0.0.0.0:8085",
+			"--upstream=http://127.0.0.1:8086/",
+			"--tls-min-version=VersionTLS13",
+		))
+		Expect(proxy.Ports).To(ConsistOf(HaveField("ContainerPort", int32(clusterAutoscalerMetricsPort))))
+	})
+})
@@ -0,0 +1,314 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+const (
+	// ownerNameLabel and ownerNamespaceLabel identify the OCIClusterAutoscaler that manages an object.
+	// Most managed objects live outside the namespace of the OCIClusterAutoscaler or are cluster-scoped,
+	// so owner references cannot be used to map them back.
+	ownerNameLabel      = "capi.openshift.io/ociclusterautoscaler-name"
+	ownerNamespaceLabel = "capi.openshift.io/ociclusterautoscaler-namespace"
+
+	// ownerUIDAnnotation records the UID of the managing OCIClusterAutoscaler, so that objects are only
+	// cleaned up by the instance that created them and not by a later one with the same name
+	ownerUIDAnnotation = "capi.openshift.io/ociclusterautoscaler-uid"
+
+	// credentialSecretsIndex indexes OCIClusterAutoscalers by the names of the private key and passphrase
+	// Secrets of their user principal
+	credentialSecretsIndex = "spec.oci.credentialSecrets"
+)
+
+// setOwnerMetadata labels and annotates obj as managed by the given OCIClusterAutoscaler. Owner references
+// are not used since Kubernetes garbage collection ignores them across namespaces and for cluster-scoped
+// objects; the objects are deleted by the finalizer of the OCIClusterAutoscaler instead.
+func setOwnerMetadata(obj client.Object, owner *capiv1alpha1.OCIClusterAutoscaler) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ownerNameLabel] = owner.Name
+	labels[ownerNamespaceLabel] = owner.Namespace
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ownerUIDAnnotation] = string(owner.UID)
+	obj.SetAnnotations(annotations)
+}
+
+// isOwnedBy returns true if obj was created by the given OCIClusterAutoscaler according to its owner
+// metadata. Objects labeled before the owner UID was recorded are attributed to the labeled owner.
+func isOwnedBy(obj client.Object, owner *capiv1alpha1.OCIClusterAutoscaler) bool {
+	labels := obj.GetLabels()
+	if labels[ownerNameLabel] != owner.Name || labels[ownerNamespaceLabel] != owner.Namespace {
+		return false
+	}
+	uid, ok := obj.GetAnnotations()[ownerUIDAnnotation]
+	return !ok || uid == string(owner.UID)
+}
+
+// ownerLabelSelector returns the labels selecting the objects managed by the given OCIClusterAutoscaler
+func ownerLabelSelector(owner *capiv1alpha1.OCIClusterAutoscaler) client.MatchingLabels {
+	return client.MatchingLabels{
+		ownerNameLabel:      owner.Name,
+		ownerNamespaceLabel: owner.Namespace,
+	}
+}
+
+// requestForOwnerLabels maps a managed object to the OCIClusterAutoscaler recorded in its owner labels
+func requestForOwnerLabels(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	name, namespace := labels[ownerNameLabel], labels[ownerNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}},
+	}
+}
+
+// requestsForCredentialSecret maps a Secret to the OCIClusterAutoscalers referencing it as their private key,
+// passphrase or OCI config file
+func (r *OCIClusterAutoscalerReconciler) requestsForCredentialSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	autoscalers := &capiv1alpha1.OCIClusterAutoscalerList{}
+	if err := r.List(ctx, autoscalers,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{credentialSecretsIndex: obj.GetName()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list OCIClusterAutoscalers for Secret", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(autoscalers.Items))
+	for _, autoscaler := range autoscalers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&autoscaler)})
+	}
+	return requests
+}
+
+// sharedObjects are the objects the operator reads without owning them, such as the controllers installed by
+// clusterctl, whose changes affect every OCIClusterAutoscaler
+var sharedObjects = map[schema.GroupKind]sets.Set[types.NamespacedName]{
+	{Group: appsv1.GroupName, Kind: "Deployment"}: sets.New(
+		types.NamespacedName{Namespace: capiSystemNamespace, Name: capiDeploymentName},
+		types.NamespacedName{Namespace: capociNamespace, Name: capociDeploymentName},
+		types.NamespacedName{Namespace: machineApproverNamespace, Name: machineApproverDeployment},
+	),
+	{Kind: "ConfigMap"}: sets.New(
+		types.NamespacedName{Namespace: machineApproverNamespace, Name: machineApproverConfigMap},
+	),
+	{Group: apiextensionsv1.GroupName, Kind: "CustomResourceDefinition"}: sets.New(
+		types.NamespacedName{Name: "clusters.cluster.x-k8s.io"},
+		types.NamespacedName{Name: "ociclusters.infrastructure.cluster.x-k8s.io"},
+		types.NamespacedName{Name: "servicemonitors.monitoring.coreos.com"},
+		types.NamespacedName{Name: "prometheusrules.monitoring.coreos.com"},
+	),
+}
+
+// requestsForSharedObject returns a mapping function enqueueing the OCIClusterAutoscaler managing an object of
+// the kind, or every OCIClusterAutoscaler when the object is one of the shared objects of the kind
+func (r *OCIClusterAutoscalerReconciler) requestsForSharedObject(kind schema.GroupKind) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if requests := requestForOwnerLabels(ctx, obj); len(requests) > 0 {
+			return requests
+		}
+		if !sharedObjects[kind].Has(client.ObjectKeyFromObject(obj)) {
+			return nil
+		}
+		return r.requestsForAllAutoscalers(ctx)
+	}
+}
+
+// requestsForAllAutoscalers enqueues every OCIClusterAutoscaler
+func (r *OCIClusterAutoscalerReconciler) requestsForAllAutoscalers(ctx context.Context) []reconcile.Request {
+	autoscalers := &capiv1alpha1.OCIClusterAutoscalerList{}
+	if err := r.List(ctx, autoscalers); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list OCIClusterAutoscalers")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(autoscalers.Items))
+	for _, autoscaler := range autoscalers.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&autoscaler)})
+	}
+	return requests
+}
+
+// autoscalerDeleted only lets through the deletion of OCIClusterAutoscalers
+var autoscalerDeleted = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	UpdateFunc:  func(event.UpdateEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// requestsForRemainingAutoscalers enqueues the other OCIClusterAutoscalers once one is deleted. The cleanup
+// of the deleted one keeps the shared objects while others exist, still labeled with its owner metadata, and
+// the remaining ones take them over by applying them again.
+func (r *OCIClusterAutoscalerReconciler) requestsForRemainingAutoscalers(ctx context.Context, deleted client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, request := range r.requestsForAllAutoscalers(ctx) {
+		if request.NamespacedName != client.ObjectKeyFromObject(deleted) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// secretNamespaceWatches starts a watch on the Secret metadata of a namespace the first time an
+// OCIClusterAutoscaler of the namespace is reconciled, so that only the namespaces that may hold credential
+// Secrets are watched
+type secretNamespaceWatches struct {
+	mu      sync.Mutex
+	watched sets.Set[string]
+	start   func(namespace string) error
+}
+
+// watch starts watching the Secrets of the namespace unless they are already watched
+func (w *secretNamespaceWatches) watch(namespace string) error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.watched.Has(namespace) {
+		return nil
+	}
+	if err := w.start(namespace); err != nil {
+		return fmt.Errorf("failed to watch the Secrets of namespace %s: %w", namespace, err)
+	}
+	w.watched.Insert(namespace)
+	return nil
+}
+
+// indexCredentialSecrets is the indexer function for credentialSecretsIndex
+func indexCredentialSecrets(obj client.Object) []string {
+	autoscaler, ok := obj.(*capiv1alpha1.OCIClusterAutoscaler)
+	if !ok {
+		return nil
+	}
+	var names []string
+	if ref := autoscaler.Spec.OCI.CredentialsConfigRef; ref != nil {
+		names = append(names, ref.Name)
+	}
+	user := userPrincipal(autoscaler)
+	if user == nil {
+		return names
+	}
+	if user.PrivateKeySecretRef.Name != "" {
+		names = append(names, user.PrivateKeySecretRef.Name)
+	}
+	if user.PassphraseSecretRef != nil && user.PassphraseSecretRef.Name != "" && user.PassphraseSecretRef.Name != user.PrivateKeySecretRef.Name {
+		names = append(names, user.PassphraseSecretRef.Name)
+	}
+	return names
+}
+
+// CacheOptions restricts the manager cache of the kinds the operator creates to the objects labeled with an
+// owner, so that the Secrets, ConfigMaps and RBAC objects of the whole cluster are not held in memory. The
+// ConfigMaps of the namespaces the operator reads unlabeled ConfigMaps from are cached entirely, and Pods
+// are only cached in the namespaces of the controllers whose admission is checked. Deployments are only
+// cached in the namespaces of the CAPI controllers, the cluster-autoscaler and the cluster-machine-approver.
+func CacheOptions() cache.Options {
+	owned, err := labels.NewRequirement(ownerNameLabel, selection.Exists, nil)
+	if err != nil {
+		panic(err)
+	}
+	selector := labels.NewSelector().Add(*owned)
+
+	byObject := map[client.Object]cache.ByObject{
+		&corev1.ConfigMap{}: {Namespaces: map[string]cache.Config{
+			cache.AllNamespaces:      {LabelSelector: selector},
+			machineApproverNamespace: {},
+		}},
+		&corev1.Pod{}: {Namespaces: map[string]cache.Config{
+			capiSystemNamespace: {},
+			capociNamespace:     {},
+		}},
+		&appsv1.Deployment{}: {Namespaces: map[string]cache.Config{
+			capiSystemNamespace:      {},
+			capociNamespace:          {},
+			machineApproverNamespace: {},
+		}},
+	}
+	for _, obj := range []client.Object{
+		&corev1.Secret{},
+		&corev1.ServiceAccount{},
+		&corev1.Service{},
+		&rbacv1.ClusterRole{},
+		&rbacv1.ClusterRoleBinding{},
+		&rbacv1.Role{},
+		&rbacv1.RoleBinding{},
+		&networkingv1.NetworkPolicy{},
+	} {
+		byObject[obj] = cache.ByObject{Label: selector}
+	}
+	return cache.Options{ByObject: byObject}
+}
+
+// isKindInstalled returns true if the API server serves the kind of obj. Optional APIs such as the CAPI
+// and OpenShift security APIs are only watched when they are installed at startup.
+func isKindInstalled(mgr ctrl.Manager, obj client.Object) (bool, error) {
+	gvk, err := mgr.GetClient().GroupVersionKindFor(obj)
+	if err != nil {
+		return false, err
+	}
+	_, err = mgr.GetRESTMapper().RESTMapping(schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// requestsForAdmittedPod enqueues every OCIClusterAutoscaler when a pod of the namespaces of the Deployments
+// whose SCC admission is checked changes. These namespaces only hold the controllers.
+func (r *OCIClusterAutoscalerReconciler) requestsForAdmittedPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	for _, admission := range sccAdmissions() {
+		if obj.GetNamespace() == admission.namespace {
+			return r.requestsForAllAutoscalers(ctx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	capiv1alpha1 "github.com/openshift/oci-capi-operator/api/v1alpha1"
+)
+
+var _ = Describe("Owner metadata", func() {
+	owner := &capiv1alpha1.OCIClusterAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "default", UID: types.UID("11111111-2222-3333-4444-555555555555")},
+	}
+
+	It("should record the owner on objects in other namespaces without owner references", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "oci-credentials",
+			Namespace:   capociNamespace,
+			Annotations: map[string]string{credentialsHashAnnotation: "hash"},
+		}}
+		setOwnerMetadata(secret, owner)
+
+		Expect(secret.OwnerReferences).To(BeEmpty())
+		Expect(secret.Labels).To(HaveKeyWithValue(ownerNameLabel, "autoscaler"))
+		Expect(secret.Labels).To(HaveKeyWithValue(ownerNamespaceLabel, "default"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(ownerUIDAnnotation, string(owner.UID)))
+		Expect(secret.Annotations).To(HaveKeyWithValue(credentialsHashAnnotation, "hash"))
+		Expect(isOwnedBy(secret, owner)).To(BeTrue())
+		Expect(requestForOwnerLabels(ctx, secret)).To(ConsistOf(HaveField("NamespacedName", types.NamespacedName{Name: "autoscaler", Namespace: "default"})))
+	})
+
+	It("should not attribute objects of a previous instance with the same name", func() {
+		clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "oci-cluster-autoscaler"}}
+		setOwnerMetadata(clusterRole, owner)
+
+		recreated := owner.DeepCopy()
+		recreated.UID = types.UID("66666666-7777-8888-9999-000000000000")
+		Expect(isOwnedBy(clusterRole, recreated)).To(BeFalse())
+	})
+
+	It("should attribute objects labeled before the owner UID was recorded", func() {
+		clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+			Name:   "oci-cluster-autoscaler",
+			Labels: map[string]string{ownerNameLabel: "autoscaler", ownerNamespaceLabel: "default"},
+		}}
+		Expect(isOwnedBy(clusterRole, owner)).To(BeTrue())
+
+		other := owner.DeepCopy()
+		other.Name = "other"
+		Expect(isOwnedBy(clusterRole, other)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Watches", func() {
+	It("should only cache the labeled Secrets and RBAC objects", func() {
+		options := CacheOptions()
+		managed := labels.Set{ownerNameLabel: "autoscaler", ownerNamespaceLabel: "default"}
+
+		for obj, byObject := range options.ByObject {
+			switch obj.(type) {
+			case *corev1.Secret, *rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding:
+				Expect(byObject.Label.Matches(managed)).To(BeTrue())
+				Expect(byObject.Label.Matches(labels.Set{"app": "other"})).To(BeFalse())
+			case *corev1.ConfigMap:
+				Expect(byObject.Namespaces[cache.AllNamespaces].LabelSelector.Matches(labels.Set{})).To(BeFalse())
+				Expect(byObject.Namespaces).To(HaveKeyWithValue(machineApproverNamespace, cache.Config{}))
+			}
+		}
+	})
+
+	It("should only cache the Deployments of the controller namespaces", func() {
+		options := CacheOptions()
+		for obj, byObject := range options.ByObject {
+			if _, ok := obj.(*appsv1.Deployment); ok {
+				Expect(byObject.Namespaces).To(HaveLen(3))
+				Expect(byObject.Namespaces).To(HaveKey(capiSystemNamespace))
+				Expect(byObject.Namespaces).To(HaveKey(capociNamespace))
+				Expect(byObject.Namespaces).To(HaveKey(machineApproverNamespace))
+				return
+			}
+		}
+		Fail("Deployments are not restricted")
+	})
+
+	It("should map a Secret to the OCIClusterAutoscalers using it as a credential", func() {
+		user := &capiv1alpha1.OCIClusterAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "user", Namespace: "default"},
+			Spec: capiv1alpha1.OCIClusterAutoscalerSpec{OCI: capiv1alpha1.OCIConfig{
+				PrivateKeySecretRef: capiv1alpha1.SecretRef{Name: "oci-key"},
+			}},
+		}
+		r := &OCIClusterAutoscalerReconciler{Client: newFakeClientBuilder().
+			WithObjects(user).
+			WithIndex(&capiv1alpha1.OCIClusterAutoscaler{}, credentialSecretsIndex, indexCredentialSecrets).
+			Build()}
+
+		key := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "oci-key", Namespace: "default"}}
+		Expect(r.requestsForCredentialSecret(ctx, key)).To(ConsistOf(HaveField("NamespacedName", types.NamespacedName{Name: "user", Namespace: "default"})))
+
+		other := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+		Expect(r.requestsForCredentialSecret(ctx, other)).To(BeEmpty())
+	})
+
+	It("should map the shared controllers to every OCIClusterAutoscaler", func() {
+		r := &OCIClusterAutoscalerReconciler{Client: newFakeClientBuilder().WithObjects(
+			&capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+			&capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "other"}},
+		).Build()}
+		requests := r.requestsForSharedObject(schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"})
+
+		capoci := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: capociDeploymentName, Namespace: capociNamespace}}
+		Expect(requests(ctx, capoci)).To(HaveLen(2))
+
+		owned := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterAutoscalerName,
+			Namespace: capiSystemNamespace,
+			Labels:    map[string]string{ownerNameLabel: "a", ownerNamespaceLabel: "default"},
+		}}
+		Expect(requests(ctx, owned)).To(ConsistOf(HaveField("NamespacedName", types.NamespacedName{Name: "a", Namespace: "default"})))
+
+		other := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: capiSystemNamespace}}
+		Expect(requests(ctx, other)).To(BeEmpty())
+	})
+
+	It("should hand the shared objects of a deleted OCIClusterAutoscaler over to the remaining ones", func() {
+		deleted := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}}
+		remaining := &capiv1alpha1.OCIClusterAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "other"}}
+		r := &OCIClusterAutoscalerReconciler{Client: newFakeClientBuilder().WithObjects(remaining).Build()}
+
+		Expect(r.requestsForRemainingAutoscalers(ctx, deleted)).
+			To(ConsistOf(HaveField("NamespacedName", client.ObjectKeyFromObject(remaining))))
+		Expect(autoscalerDeleted.Delete(event.DeleteEvent{Object: deleted})).To(BeTrue())
+		Expect(autoscalerDeleted.Create(event.CreateEvent{Object: deleted})).To(BeFalse())
+		Expect(autoscalerDeleted.Update(event.UpdateEvent{ObjectOld: deleted, ObjectNew: deleted})).To(BeFalse())
+	})
+
+	It("should watch the Secrets of a namespace once", func() {
+		var started []string
+		watches := &secretNamespaceWatches{watched: sets.New[string](), start: func(namespace string) error {
+			started = append(started, namespace)
+			return nil
+		}}
+		Expect(watches.watch("default")).To(Succeed())
+		Expect(watches.watch("default")).To(Succeed())
+		Expect(watches.watch("other")).To(Succeed())
+		Expect(started).To(Equal([]string{"default", "other"}))
+
+		var unset *secretNamespaceWatches
+		Expect(unset.watch("default")).To(Succeed())
+	})
+})